@@ -0,0 +1,84 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/locale"
+)
+
+// TestRunDateFormatNumericSpecifiers checks that the numeric/week/year
+// specifiers are handled directly by the opcode table (appendPadInt)
+// rather than falling through to the scalar t.DateFormat default case.
+func TestRunDateFormatNumericSpecifiers(t *testing.T) {
+	// 2024-03-05 is a Tuesday, day-of-year 65.
+	tm := types.Time{
+		Time: types.FromDate(2024, 3, 5, 13, 4, 9, 0),
+		Type: mysql.TypeDatetime,
+		Fsp:  types.DefaultFsp,
+	}
+	names := locale.Get("")
+	cases := []struct {
+		format string
+		want   string
+	}{
+		{"%Y-%m-%d", "2024-03-05"},
+		{"%H:%i:%s", "13:04:09"},
+		{"%j", "065"},
+	}
+	for _, c := range cases {
+		prog := compileDateFormat(c.format)
+		got, err := runDateFormat(tm, prog, names, nil)
+		if err != nil {
+			t.Fatalf("runDateFormat(%q): %v", c.format, err)
+		}
+		if string(got) != c.want {
+			t.Errorf("runDateFormat(%q) = %q, want %q", c.format, got, c.want)
+		}
+	}
+}
+
+// TestRunDateFormatReusesScratchBuffer checks that passing the same
+// underlying slice back in for a second row doesn't leak the first row's
+// content past its own length.
+func TestRunDateFormatReusesScratchBuffer(t *testing.T) {
+	tm := types.Time{
+		Time: types.FromDate(2024, 3, 5, 13, 4, 9, 0),
+		Type: mysql.TypeDatetime,
+		Fsp:  types.DefaultFsp,
+	}
+	names := locale.Get("")
+	prog := compileDateFormat("%Y")
+	scratch := make([]byte, 0, 16)
+
+	scratch, err := runDateFormat(tm, prog, names, scratch[:0])
+	if err != nil {
+		t.Fatalf("runDateFormat: %v", err)
+	}
+	if string(scratch) != "2024" {
+		t.Fatalf("first call = %q, want %q", scratch, "2024")
+	}
+
+	tm.Time = types.FromDate(5, 3, 5, 13, 4, 9, 0)
+	scratch, err = runDateFormat(tm, prog, names, scratch[:0])
+	if err != nil {
+		t.Fatalf("runDateFormat: %v", err)
+	}
+	if string(scratch) != "0005" {
+		t.Errorf("second call = %q, want %q", scratch, "0005")
+	}
+}
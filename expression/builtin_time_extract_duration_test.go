@@ -0,0 +1,82 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/types"
+)
+
+// TestExtractDurationNum checks the single-field and composite EXTRACT
+// units against a duration with a distinct value in every field, and that
+// a negative duration's sign is applied to the whole result rather than
+// dropped.
+func TestExtractDurationNum(t *testing.T) {
+	d, err := types.NewDurationFromParts(false, 27, 8, 9, 500000, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	cases := []struct {
+		unit string
+		want int64
+	}{
+		{"MICROSECOND", 500000},
+		{"SECOND", 9},
+		{"MINUTE", 8},
+		{"HOUR", 27},
+		{"DAY", 1},
+		{"SECOND_MICROSECOND", 9500000},
+		{"MINUTE_SECOND", 809},
+		{"HOUR_MINUTE", 2708},
+		{"DAY_HOUR", 103},
+	}
+	for _, c := range cases {
+		got, err := extractDurationNum(d, c.unit)
+		if err != nil {
+			t.Fatalf("extractDurationNum(%q): %v", c.unit, err)
+		}
+		if got != c.want {
+			t.Errorf("extractDurationNum(%q) = %d, want %d", c.unit, got, c.want)
+		}
+	}
+}
+
+// TestExtractDurationNumNegativeSign checks that a negative duration's
+// EXTRACT result carries the sign on the whole composite value, not just
+// the leading field.
+func TestExtractDurationNumNegativeSign(t *testing.T) {
+	d, err := types.NewDurationFromParts(true, 1, 2, 3, 0, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	got, err := extractDurationNum(d, "HOUR_SECOND")
+	if err != nil {
+		t.Fatalf("extractDurationNum: %v", err)
+	}
+	if want := int64(-10203); got != want {
+		t.Errorf("extractDurationNum(HOUR_SECOND) = %d, want %d", got, want)
+	}
+}
+
+// TestExtractDurationNumUnknownUnit checks the error path.
+func TestExtractDurationNumUnknownUnit(t *testing.T) {
+	d, err := types.NewDurationFromParts(false, 1, 0, 0, 0, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	if _, err := extractDurationNum(d, "NOT_A_UNIT"); err == nil {
+		t.Fatalf("extractDurationNum(\"NOT_A_UNIT\") succeeded, want an error")
+	}
+}
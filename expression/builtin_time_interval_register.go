@@ -0,0 +1,76 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+)
+
+// TiDBAddInterval and TiDBSubInterval are the function names
+// builtinAddIntervalSig/builtinSubIntervalSig are reachable under.
+//
+// They are not registered as DATE_ADD/DATE_SUB themselves: that would
+// require reading the unit off the parsed `INTERVAL expr unit` grammar at
+// plan-build time the way DATE_ADD's real functionClass does, which in turn
+// needs the ast/Constant infrastructure this tree doesn't carry (see
+// interval_expression.go's commit message for the same limitation hit by
+// TIMESTAMPADD). Taking the unit as an ordinary third string argument avoids
+// guessing at that unbuilt API, while still exercising the exact same
+// vecEvalIntervalArith path DATE_ADD/DATE_SUB would use once that wiring
+// exists.
+const (
+	TiDBAddInterval = "tidb_add_interval"
+	TiDBSubInterval = "tidb_sub_interval"
+)
+
+// tidbAddIntervalFunctionClass implements TIDB_ADD_INTERVAL(datetime, n, unit).
+type tidbAddIntervalFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbAddIntervalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinAddIntervalSig{bf}, nil
+}
+
+// tidbSubIntervalFunctionClass implements TIDB_SUB_INTERVAL(datetime, n, unit).
+type tidbSubIntervalFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbSubIntervalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinSubIntervalSig{bf}, nil
+}
+
+// init registers builtinAddIntervalSig/builtinSubIntervalSig into funcs.
+// Without this, as the review on this series pointed out, both sigs were
+// unreachable from any SQL statement despite having a working vecEval path.
+func init() {
+	funcs[TiDBAddInterval] = &tidbAddIntervalFunctionClass{baseFunctionClass{TiDBAddInterval, 3, 3}}
+	funcs[TiDBSubInterval] = &tidbSubIntervalFunctionClass{baseFunctionClass{TiDBSubInterval, 3, 3}}
+}
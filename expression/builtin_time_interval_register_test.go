@@ -0,0 +1,28 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import "testing"
+
+// TestTiDBIntervalFunctionsRegistered guards against
+// builtinAddIntervalSig/builtinSubIntervalSig going the same way they did
+// before this test existed: a working vecEval path with no funcs entry,
+// unreachable from any SQL statement.
+func TestTiDBIntervalFunctionsRegistered(t *testing.T) {
+	for _, name := range []string{TiDBAddInterval, TiDBSubInterval} {
+		if _, ok := funcs[name]; !ok {
+			t.Fatalf("%s was not registered in funcs", name)
+		}
+	}
+}
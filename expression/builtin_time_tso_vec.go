@@ -0,0 +1,143 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// builtinTidbBuildTsoSig implements TIDB_BUILD_TSO(datetime, logical), the
+// inverse of TIDB_PARSE_TSO: it packs a datetime plus a logical counter back
+// into a single TSO value.
+type builtinTidbBuildTsoSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTidbBuildTsoSig) Clone() builtinFunc {
+	newSig := &builtinTidbBuildTsoSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTidbBuildTsoSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTidbBuildTsoSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err := b.args[0].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+
+	bufLogical, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufLogical)
+	if err := b.args[1].VecEvalInt(b.ctx, input, bufLogical); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufTime, bufLogical)
+	i64s := result.Int64s()
+	ds := bufTime.Times()
+	logicals := bufLogical.Int64s()
+	loc := b.ctx.GetSessionVars().Location()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		gt, err := ds[i].Time.GoTime(loc)
+		if err != nil {
+			return err
+		}
+		physical := oracle.GetPhysical(gt.In(time.Local))
+		i64s[i] = int64(oracle.ComposeTS(physical, logicals[i]))
+	}
+	return nil
+}
+
+// TidbTsoRangeBounds returns the inclusive [minTSO, maxTSO] window that
+// covers every TSO whose decoded physical time falls within [start, end] in
+// loc. It's the shared primitive behind both TIDB_TSO_RANGE and the
+// optimizer's TIDB_PARSE_TSO(col) BETWEEN t1 AND t2 -> col BETWEEN
+// TIDB_BUILD_TSO(t1, 0) AND TIDB_BUILD_TSO(t2, max_logical) predicate
+// rewrite, so a range scan stays index-friendly instead of evaluating
+// TIDB_PARSE_TSO per row.
+func TidbTsoRangeBounds(start, end types.Time, loc *time.Location) (minTSO, maxTSO uint64, err error) {
+	startGoTime, err := start.Time.GoTime(loc)
+	if err != nil {
+		return 0, 0, err
+	}
+	endGoTime, err := end.Time.GoTime(loc)
+	if err != nil {
+		return 0, 0, err
+	}
+	minTSO = oracle.TSFromPhysical(oracle.GetPhysical(startGoTime.In(time.Local)), false)
+	maxTSO = oracle.TSFromPhysical(oracle.GetPhysical(endGoTime.In(time.Local)), true)
+	return minTSO, maxTSO, nil
+}
+
+// RewriteTidbParseTsoRangePredicate computes the index-friendly replacement
+// for `TIDB_PARSE_TSO(col) BETWEEN t1 AND t2`: `col BETWEEN lower AND upper`,
+// using TidbTsoRangeBounds for the bound computation. It's a pure helper,
+// not a plan-rewrite rule wired into an optimizer pass — this tree has no
+// planner/logical-optimizer package for a rule to live in, so the actual
+// `col BETWEEN TIDB_BUILD_TSO(t1, 0) AND TIDB_BUILD_TSO(t2, max_logical)`
+// rewrite this helper implements is callable directly by any future rule
+// that gets added, rather than by a bundled one here.
+func RewriteTidbParseTsoRangePredicate(t1, t2 types.Time, loc *time.Location) (lower, upper uint64, err error) {
+	return TidbTsoRangeBounds(t1, t2, loc)
+}
+
+// TidbTsoRangePhysicalWindow is the row shape TIDB_TSO_RANGE(start_ts,
+// end_ts) would project: the inclusive Unix-epoch-millisecond window
+// [MinPhysical, MaxPhysical] that TidbTsoRangeBounds' [minTSO, maxTSO]
+// decodes to.
+//
+// TIDB_TSO_RANGE itself is NOT registered as a callable function: this
+// tree's expression registry (funcs, baseFunctionClass, getFunction) only
+// supports scalar functions returning one value per row, and there is no
+// FROM-clause/table-function mechanism anywhere in this tree for a
+// multi-row result like a TSO range window to be projected through. Until
+// one exists, TidbTsoRangePhysicalWindow and TidbTsoRangeBounds are the
+// callable primitives a future table-function implementation would sit
+// on top of.
+type TidbTsoRangePhysicalWindow struct {
+	MinPhysical int64
+	MaxPhysical int64
+}
+
+// TidbTsoRangeWindow computes the physical-time window TIDB_TSO_RANGE(start,
+// end) should report, via TidbTsoRangeBounds plus oracle.ExtractPhysical.
+func TidbTsoRangeWindow(start, end types.Time, loc *time.Location) (TidbTsoRangePhysicalWindow, error) {
+	minTSO, maxTSO, err := TidbTsoRangeBounds(start, end, loc)
+	if err != nil {
+		return TidbTsoRangePhysicalWindow{}, err
+	}
+	return TidbTsoRangePhysicalWindow{
+		MinPhysical: oracle.ExtractPhysical(minTSO),
+		MaxPhysical: oracle.ExtractPhysical(maxTSO),
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/types"
+)
+
+func tsoTestTime(t time.Time) types.Time {
+	return types.Time{Time: types.FromGoTime(t), Type: mysql.TypeDatetime, Fsp: types.DefaultFsp}
+}
+
+// TestTidbTsoRangeBoundsCoversWindow checks that every TSO composed from a
+// physical time inside [start, end] falls within [minTSO, maxTSO], and one
+// composed from just outside the window doesn't.
+func TestTidbTsoRangeBoundsCoversWindow(t *testing.T) {
+	loc := time.UTC
+	start := tsoTestTime(time.Date(2024, 3, 5, 12, 0, 0, 0, loc))
+	end := tsoTestTime(time.Date(2024, 3, 5, 13, 0, 0, 0, loc))
+
+	minTSO, maxTSO, err := TidbTsoRangeBounds(start, end, loc)
+	if err != nil {
+		t.Fatalf("TidbTsoRangeBounds: %v", err)
+	}
+	if minTSO >= maxTSO {
+		t.Fatalf("minTSO %d should be < maxTSO %d", minTSO, maxTSO)
+	}
+
+	inside := oracle.ComposeTS(oracle.GetPhysical(time.Date(2024, 3, 5, 12, 30, 0, 0, loc)), 5)
+	if inside < minTSO || inside > maxTSO {
+		t.Errorf("TSO %d at 12:30 should fall within [%d, %d]", inside, minTSO, maxTSO)
+	}
+
+	before := oracle.ComposeTS(oracle.GetPhysical(time.Date(2024, 3, 5, 11, 0, 0, 0, loc)), 0)
+	if before >= minTSO {
+		t.Errorf("TSO %d at 11:00 should fall below minTSO %d", before, minTSO)
+	}
+}
+
+// TestTidbTsoRangeWindowRoundTripsPhysicalTime checks that
+// TidbTsoRangeWindow's reported window decodes back to the same
+// Unix-millisecond bounds TidbTsoRangeBounds' TSOs carry.
+func TestTidbTsoRangeWindowRoundTripsPhysicalTime(t *testing.T) {
+	loc := time.UTC
+	start := tsoTestTime(time.Date(2024, 3, 5, 12, 0, 0, 0, loc))
+	end := tsoTestTime(time.Date(2024, 3, 5, 13, 0, 0, 0, loc))
+
+	window, err := TidbTsoRangeWindow(start, end, loc)
+	if err != nil {
+		t.Fatalf("TidbTsoRangeWindow: %v", err)
+	}
+	minTSO, maxTSO, err := TidbTsoRangeBounds(start, end, loc)
+	if err != nil {
+		t.Fatalf("TidbTsoRangeBounds: %v", err)
+	}
+	if window.MinPhysical != oracle.ExtractPhysical(minTSO) || window.MaxPhysical != oracle.ExtractPhysical(maxTSO) {
+		t.Errorf("window = %+v, want physical bounds derived from [%d, %d]", window, minTSO, maxTSO)
+	}
+}
+
+// TestRewriteTidbParseTsoRangePredicateMatchesBounds checks that the
+// predicate-rewrite helper returns the same bounds TidbTsoRangeBounds
+// computes directly, since it's meant to be a thin named wrapper over it.
+func TestRewriteTidbParseTsoRangePredicateMatchesBounds(t *testing.T) {
+	loc := time.UTC
+	t1 := tsoTestTime(time.Date(2024, 1, 1, 0, 0, 0, 0, loc))
+	t2 := tsoTestTime(time.Date(2024, 1, 2, 0, 0, 0, 0, loc))
+
+	lower, upper, err := RewriteTidbParseTsoRangePredicate(t1, t2, loc)
+	if err != nil {
+		t.Fatalf("RewriteTidbParseTsoRangePredicate: %v", err)
+	}
+	wantLower, wantUpper, err := TidbTsoRangeBounds(t1, t2, loc)
+	if err != nil {
+		t.Fatalf("TidbTsoRangeBounds: %v", err)
+	}
+	if lower != wantLower || upper != wantUpper {
+		t.Errorf("RewriteTidbParseTsoRangePredicate = (%d, %d), want (%d, %d)", lower, upper, wantLower, wantUpper)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+)
+
+// Function names for the builtins added earlier in this series. None of
+// them need new parser grammar: like TIDB_PARSE_TSO and friends, they're
+// ordinary `NAME(args)` function calls, which the grammar already resolves
+// generically by looking the name up in funcs. What they were missing is
+// the funcs entry itself.
+const (
+	NowTZ                = "now_tz"
+	SysDateTZ            = "sysdate_tz"
+	UTCTimeTZ            = "utc_time_tz"
+	TiDBParseTsoTZ       = "tidb_parse_tso_tz"
+	TiDBBuildTso         = "tidb_build_tso"
+	FromUnixTimeNano     = "from_unixtime_nano"
+	UnixTimestampNano    = "unix_timestamp_nano"
+	CurrentTimestampNano = "current_timestamp_nano"
+)
+
+// nowTZFunctionClass builds the *TZ twin of NOW(): it takes the same
+// optional fsp argument, but the resulting builtinNowWithArgTZSig tags the
+// value with the session's zone name instead of discarding it.
+type nowTZFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nowTZFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinNowWithArgTZSig{bf}, nil
+}
+
+// sysDateTZFunctionClass builds the *TZ twin of SYSDATE(fsp).
+type sysDateTZFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sysDateTZFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinSysDateWithFspTZSig{bf}, nil
+}
+
+// utcTimeTZFunctionClass builds the *TZ twin of UTC_TIME(fsp), tagging the
+// result with the "UTC" zone instead of discarding it.
+type utcTimeTZFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcTimeTZFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinUTCTimeWithArgTZSig{bf}, nil
+}
+
+// tidbParseTsoTZFunctionClass builds the *TZ twin of TIDB_PARSE_TSO(tso),
+// keeping the decoded datetime tagged "UTC" instead of converting it into
+// the session's Location().
+type tidbParseTsoTZFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbParseTsoTZFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinTidbParseTsoTZSig{bf}, nil
+}
+
+// tidbBuildTsoFunctionClass implements TIDB_BUILD_TSO(datetime, logical),
+// the inverse of TIDB_PARSE_TSO.
+type tidbBuildTsoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbBuildTsoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinTidbBuildTsoSig{bf}, nil
+}
+
+// fromUnixTimeNanoFunctionClass implements FROM_UNIXTIME_NANO(bigint).
+type fromUnixTimeNanoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fromUnixTimeNanoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinFromUnixTimeNanoSig{bf}, nil
+}
+
+// unixTimestampNanoFunctionClass implements UNIX_TIMESTAMP_NANO().
+type unixTimestampNanoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unixTimestampNanoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinUnixTimestampNanoSig{bf}, nil
+}
+
+// currentTimestampNanoFunctionClass implements CURRENT_TIMESTAMP_NANO().
+type currentTimestampNanoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentTimestampNanoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinCurrentTimestampNanoSig{bf}, nil
+}
+
+// init registers every builtin added earlier in this series into funcs,
+// the step each of those commits skipped: without an entry here, the
+// planner has nothing to resolve the call against, and it fails to
+// validate despite the signature/vecEval plumbing otherwise working.
+func init() {
+	funcs[NowTZ] = &nowTZFunctionClass{baseFunctionClass{NowTZ, 0, 1}}
+	funcs[SysDateTZ] = &sysDateTZFunctionClass{baseFunctionClass{SysDateTZ, 0, 1}}
+	funcs[UTCTimeTZ] = &utcTimeTZFunctionClass{baseFunctionClass{UTCTimeTZ, 0, 1}}
+	funcs[TiDBParseTsoTZ] = &tidbParseTsoTZFunctionClass{baseFunctionClass{TiDBParseTsoTZ, 1, 1}}
+	funcs[TiDBBuildTso] = &tidbBuildTsoFunctionClass{baseFunctionClass{TiDBBuildTso, 2, 2}}
+	funcs[FromUnixTimeNano] = &fromUnixTimeNanoFunctionClass{baseFunctionClass{FromUnixTimeNano, 1, 1}}
+	funcs[UnixTimestampNano] = &unixTimestampNanoFunctionClass{baseFunctionClass{UnixTimestampNano, 0, 0}}
+	funcs[CurrentTimestampNano] = &currentTimestampNanoFunctionClass{baseFunctionClass{CurrentTimestampNano, 0, 0}}
+}
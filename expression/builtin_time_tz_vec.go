@@ -0,0 +1,231 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// builtinNowWithArgTZSig is like builtinNowWithArgSig but preserves the
+// session's origin zone name on the value instead of collapsing to it, so
+// cross-region callers can tell which zone a result came from.
+type builtinNowWithArgTZSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowWithArgTZSig) Clone() builtinFunc {
+	newSig := &builtinNowWithArgTZSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNowWithArgTZSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinNowWithArgTZSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufFsp, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufFsp)
+	if err = b.args[0].VecEvalInt(b.ctx, input, bufFsp); err != nil {
+		return err
+	}
+
+	zone := b.ctx.GetSessionVars().Location().String()
+	fsps := bufFsp.Int64s()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		fsp := int8(0)
+		if !bufFsp.IsNull(i) {
+			if fsps[i] > int64(types.MaxFsp) || fsps[i] < int64(types.MinFsp) {
+				return errors.Errorf("Invalid fsp %d specified for 'now', must in [0, 6].", fsps[i])
+			}
+			fsp = int8(fsps[i])
+		}
+		t, isNull, err := evalNowWithFsp(b.ctx, fsp)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		tz, err := types.NewTimeZone(t, zone, fsp)
+		if err != nil {
+			return err
+		}
+		result.AppendString(tz.String())
+	}
+	return nil
+}
+
+// builtinSysDateWithFspTZSig is the *TZSig twin of builtinSysDateWithFspSig.
+type builtinSysDateWithFspTZSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSysDateWithFspTZSig) Clone() builtinFunc {
+	newSig := &builtinSysDateWithFspTZSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinSysDateWithFspTZSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSysDateWithFspTZSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err = b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	loc := b.ctx.GetSessionVars().Location()
+	now := time.Now().In(loc)
+	zone := loc.String()
+	ds := buf.Int64s()
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		fsp := int8(ds[i])
+		t, err := convertTimeToMysqlTime(now, fsp, types.ModeHalfEven)
+		if err != nil {
+			return err
+		}
+		tz, err := types.NewTimeZone(t, zone, fsp)
+		if err != nil {
+			return err
+		}
+		result.AppendString(tz.String())
+	}
+	return nil
+}
+
+// builtinUTCTimeWithArgTZSig is the *TZSig twin of builtinUTCTimeWithArgSig,
+// tagging the result with the "UTC" zone instead of discarding it.
+type builtinUTCTimeWithArgTZSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTimeWithArgTZSig) Clone() builtinFunc {
+	newSig := &builtinUTCTimeWithArgTZSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUTCTimeWithArgTZSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUTCTimeWithArgTZSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+	nowTs, err := getStmtTimestamp(b.ctx)
+	if err != nil {
+		return err
+	}
+	utc := nowTs.UTC()
+	i64s := buf.Int64s()
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		fsp := i64s[i]
+		if fsp > int64(types.MaxFsp) || fsp < int64(types.MinFsp) {
+			return errors.Errorf("Invalid fsp %d specified for 'utc_time', must in [0, 6].", fsp)
+		}
+		t := types.Time{Time: types.FromGoTime(utc), Type: mysql.TypeDatetime, Fsp: int8(fsp)}
+		tz, err := types.NewTimeZone(t, "UTC", int8(fsp))
+		if err != nil {
+			return err
+		}
+		result.AppendString(tz.String())
+	}
+	return nil
+}
+
+// builtinTidbParseTsoTZSig is the *TZSig twin of builtinTidbParseTsoSig: it
+// keeps the TSO's physical-time zone ("UTC", since TSO physical time is wall
+// clock UTC millis) attached to the decoded datetime instead of silently
+// converting into the session's Location().
+type builtinTidbParseTsoTZSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTidbParseTsoTZSig) Clone() builtinFunc {
+	newSig := &builtinTidbParseTsoTZSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTidbParseTsoTZSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTidbParseTsoTZSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+	args := buf.Int64s()
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || args[i] <= 0 {
+			result.AppendNull()
+			continue
+		}
+		gt := oracle.GetTimeFromTS(uint64(args[i]))
+		t := types.Time{Time: types.FromGoTime(gt.UTC()), Type: mysql.TypeDatetime, Fsp: types.MaxFsp}
+		tz, err := types.NewTimeZone(t, "UTC", types.MaxFsp)
+		if err != nil {
+			return err
+		}
+		result.AppendString(tz.String())
+	}
+	return nil
+}
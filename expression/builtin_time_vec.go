@@ -14,7 +14,6 @@
 package expression
 
 import (
-	"fmt"
 	"math"
 	"strconv"
 	"strings"
@@ -23,12 +22,103 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/locale"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
+// sessionLocaleNames looks up the weekday/month name table for the session's
+// lc_time_names, falling back to en_US if unset. Callers should invoke this
+// once per vecEval call, outside the row loop, to avoid a map lookup per row.
+func sessionLocaleNames(ctx sessionctx.Context) *locale.Names {
+	lc, _ := ctx.GetSessionVars().GetSystemVar(variable.LcTimeNames)
+	return locale.Get(lc)
+}
+
+// extendedDateRangeEnabled reports whether tidb_extended_date_range is ON,
+// in which case callers should parse and compute over BC-era / negative-year
+// dates instead of rejecting them the way MySQL's native year-1-to-9999
+// range does.
+func extendedDateRangeEnabled(ctx sessionctx.Context) bool {
+	val, ok := ctx.GetSessionVars().GetSystemVar(variable.TiDBExtendedDateRange)
+	return ok && variable.TiDBOptOn(val)
+}
+
+// zeroDateAsNullEnabled reports whether tidb_zero_date_as_null is ON. When
+// it is, a zero-valued or otherwise InvalidZero() datetime should become a
+// silent NULL instead of going through handleInvalidTimeError's
+// SQLMode-driven warn/error decision, regardless of NoZeroDate/NoZeroInDate.
+func zeroDateAsNullEnabled(ctx sessionctx.Context) bool {
+	val, ok := ctx.GetSessionVars().GetSystemVar(variable.TiDBZeroDateAsNull)
+	return ok && variable.TiDBOptOn(val)
+}
+
+// zeroOrInvalidTimeIsNull centralizes the tidb_zero_date_as_null check this
+// file used to duplicate ahead of every handleInvalidTimeError call: when
+// zeroDateAsNullEnabled, t becomes a silent NULL without ever reaching
+// handleInvalidTimeError; otherwise handleInvalidTimeError runs exactly as
+// it did at each of these call sites, and the caller still nulls the value
+// out whenever it doesn't return an error (handleInvalidTimeError's own
+// SQLMode-driven warn/error decision already covers the non-strict case).
+//
+// handleInvalidTimeError itself is defined outside this tree (it isn't
+// present anywhere in this repo snapshot), so the check can't be folded
+// into its body directly; this is the local equivalent, called from one
+// place instead of re-checking zeroDateAsNullEnabled at each of the sites
+// below.
+func zeroOrInvalidTimeIsNull(ctx sessionctx.Context, t types.Time) (isNull bool, err error) {
+	if zeroDateAsNullEnabled(ctx) {
+		return true, nil
+	}
+	if err := handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String())); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// zeroOrInvalidTimeIsNullForArgs is zeroOrInvalidTimeIsNull's two-operand
+// counterpart, for builtins (DATEDIFF and friends) where either operand
+// can independently be zero/invalid. arg1's handleInvalidTimeError call
+// takes priority over arg0's when both are invalid, matching the order the
+// duplicated code at each of these call sites already used.
+func zeroOrInvalidTimeIsNullForArgs(ctx sessionctx.Context, arg0Invalid bool, arg0 types.Time, arg1Invalid bool, arg1 types.Time) (isNull bool, err error) {
+	if zeroDateAsNullEnabled(ctx) {
+		return true, nil
+	}
+	if arg0Invalid {
+		err = handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, arg0.String()))
+	}
+	if arg1Invalid {
+		err = handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, arg1.String()))
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// zeroDateFallback centralizes the zero-date decision MONTH/YEAR/DAYOFMONTH
+// make: null under tidb_zero_date_as_null, null-via-warn-or-error under
+// NoZeroDateMode (through handleInvalidTimeError), or a numeric 0 fallback
+// when neither applies. A false, nil result means the caller should use
+// its own zero-value fallback.
+func zeroDateFallback(ctx sessionctx.Context, t types.Time) (isNull bool, err error) {
+	if zeroDateAsNullEnabled(ctx) {
+		return true, nil
+	}
+	if ctx.GetSessionVars().SQLMode.HasNoZeroDateMode() {
+		if err := handleInvalidTimeError(ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String())); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 func (b *builtinMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	buf, err := b.bufAllocator.get(types.ETDatetime, n)
@@ -49,10 +139,11 @@ func (b *builtinMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 			continue
 		}
 		if ds[i].IsZero() {
-			if b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode() {
-				if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
-					return err
-				}
+			isNull, err := zeroDateFallback(b.ctx, ds[i])
+			if err != nil {
+				return err
+			}
+			if isNull {
 				result.SetNull(i, true)
 				continue
 			}
@@ -88,10 +179,11 @@ func (b *builtinYearSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) er
 			continue
 		}
 		if ds[i].IsZero() {
-			if b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode() {
-				if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
-					return err
-				}
+			isNull, err := zeroDateFallback(b.ctx, ds[i])
+			if err != nil {
+				return err
+			}
+			if isNull {
 				result.SetNull(i, true)
 				continue
 			}
@@ -286,10 +378,11 @@ func (b *builtinDayNameSig) vecEvalIndex(input *chunk.Chunk, apply func(i, res i
 func (b *builtinDayNameSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	result.ReserveString(n)
+	names := sessionLocaleNames(b.ctx)
 
 	return b.vecEvalIndex(input,
 		func(i, res int) {
-			result.AppendString(types.WeekdayNames[res])
+			result.AppendString(names.WeekdayNames[res])
 		},
 		func(i int) {
 			result.AppendNull()
@@ -748,13 +841,23 @@ func (b *builtinStrToDateDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.
 	result.MergeNulls(bufStrings, bufFormats)
 	times := result.Times()
 	sc := b.ctx.GetSessionVars().StmtCtx
+	strict := strToDateStrictMode(b.ctx)
+	formatCache := make(map[string][]string, 1)
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
 			continue
 		}
-		var t types.Time
-		succ := t.StrToDate(sc, bufStrings.GetString(i), bufFormats.GetString(i))
+		formatStr := bufFormats.GetString(i)
+		formats, ok := formatCache[formatStr]
+		if !ok {
+			formats = splitStrToDateFormats(formatStr)
+			formatCache[formatStr] = formats
+		}
+		t, succ := tryStrToDateFormats(sc, bufStrings.GetString(i), formats)
 		if !succ {
+			if strict {
+				return types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, bufStrings.GetString(i))
+			}
 			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String())); err != nil {
 				return err
 			}
@@ -774,6 +877,39 @@ func (b *builtinStrToDateDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.
 	return nil
 }
 
+// splitStrToDateFormats splits a STR_TO_DATE format argument on commas into
+// the list of candidate formats to try in order, so heterogeneous ISO /
+// RFC3339 / MySQL / European date literals can be ingested from one column.
+func splitStrToDateFormats(formatStr string) []string {
+	parts := strings.Split(formatStr, ",")
+	formats := make([]string, 0, len(parts))
+	for _, p := range parts {
+		formats = append(formats, strings.TrimSpace(p))
+	}
+	return formats
+}
+
+// tryStrToDateFormats tries each candidate format in order and returns the
+// first successful parse.
+func tryStrToDateFormats(sc *stmtctx.StatementContext, str string, formats []string) (types.Time, bool) {
+	var t types.Time
+	for _, format := range formats {
+		var candidate types.Time
+		if candidate.StrToDate(sc, str, format) {
+			return candidate, true
+		}
+	}
+	return t, false
+}
+
+// strToDateStrictMode reports whether tidb_str_to_date_strict is ON, in
+// which case a total STR_TO_DATE parse failure raises an error instead of
+// returning NULL.
+func strToDateStrictMode(ctx sessionctx.Context) bool {
+	val, ok := ctx.GetSessionVars().GetSystemVar(variable.TiDBStrToDateStrict)
+	return ok && variable.TiDBOptOn(val)
+}
+
 func (b *builtinSysDateWithFspSig) vectorized() bool {
 	return true
 }
@@ -851,11 +987,61 @@ func (b *builtinTidbParseTsoSig) vecEvalTime(input *chunk.Chunk, result *chunk.C
 }
 
 func (b *builtinSubStringAndDurationSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalString evals a builtinSubStringAndDurationSig, i.e. SUBTIME(str, duration).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_subtime
 func (b *builtinSubStringAndDurationSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err = b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+
+	buf1, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err = b.args[1].VecEvalDuration(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) || buf1.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+
+		arg0 := buf0.GetString(i)
+		arg1 := buf1.GetDuration(i, 0)
+		if isDuration(arg0) {
+			res, err := strDurationSubDuration(sc, arg0, arg1)
+			if err != nil {
+				if terror.ErrorEqual(err, types.ErrTruncatedWrongVal) {
+					sc.AppendWarning(err)
+					result.AppendNull()
+					continue
+				}
+				return err
+			}
+			result.AppendString(res)
+			continue
+		}
+		res, err := strDatetimeSubDuration(sc, arg0, arg1)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
 }
 
 func (b *builtinFromDaysSig) vectorized() bool {
@@ -1013,11 +1199,105 @@ func (b *builtinWeekWithModeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Co
 }
 
 func (b *builtinExtractDurationSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalInt evals EXTRACT(unit FROM duration). Unlike
+// builtinExtractDatetimeSig's types.ExtractDatetimeNum, there's no shared
+// helper for a Duration source, so extractDurationNum below computes the
+// single-field and composite units directly off the Duration's own
+// Hour/Minute/Second accessors.
 func (b *builtinExtractDurationSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	bufUnit, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufUnit)
+	if err := b.args[0].VecEvalString(b.ctx, input, bufUnit); err != nil {
+		return err
+	}
+
+	bufDur, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDur)
+	if err := b.args[1].VecEvalDuration(b.ctx, input, bufDur); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufUnit, bufDur)
+	i64s := result.Int64s()
+	fsp := b.args[1].GetType().Decimal
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		dur := bufDur.GetDuration(i, int(fsp))
+		res, err := extractDurationNum(dur, bufUnit.GetString(i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+// extractDurationNum computes EXTRACT(unit FROM d) for the single-field
+// units (MICROSECOND/SECOND/MINUTE/HOUR/DAY, DAY here meaning whole 24h
+// periods of d's hour component, since a TIME value carries no date of its
+// own) and the composite units MySQL defines as concatenations of adjacent
+// fields (e.g. HOUR_SECOND = hour*10000 + minute*100 + second), applying
+// d's sign to the whole result the same way TIME_TO_SEC does.
+func extractDurationNum(d types.Duration, unit string) (int64, error) {
+	sign := int64(1)
+	if d.IsNeg() {
+		sign = -1
+	}
+	hour, minute, second := int64(d.Hour()), int64(d.Minute()), int64(d.Second())
+	microsecond := int64((d.Duration % time.Second) / time.Microsecond)
+	if microsecond < 0 {
+		microsecond = -microsecond
+	}
+	day := hour / 24
+	hourOfDay := hour % 24
+
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND":
+		return sign * microsecond, nil
+	case "SECOND":
+		return sign * second, nil
+	case "MINUTE":
+		return sign * minute, nil
+	case "HOUR":
+		return sign * hour, nil
+	case "DAY":
+		return sign * day, nil
+	case "SECOND_MICROSECOND":
+		return sign * (second*1000000 + microsecond), nil
+	case "MINUTE_MICROSECOND":
+		return sign * (minute*100000000 + second*1000000 + microsecond), nil
+	case "MINUTE_SECOND":
+		return sign * (minute*100 + second), nil
+	case "HOUR_MICROSECOND":
+		return sign * (hour*10000000000 + minute*100000000 + second*1000000 + microsecond), nil
+	case "HOUR_SECOND":
+		return sign * (hour*10000 + minute*100 + second), nil
+	case "HOUR_MINUTE":
+		return sign * (hour*100 + minute), nil
+	case "DAY_MICROSECOND":
+		return sign * (day*1000000000000 + hourOfDay*10000000000 + minute*100000000 + second*1000000 + microsecond), nil
+	case "DAY_SECOND":
+		return sign * (day*1000000 + hourOfDay*10000 + minute*100 + second), nil
+	case "DAY_MINUTE":
+		return sign * (day*10000 + hourOfDay*100 + minute), nil
+	case "DAY_HOUR":
+		return sign * (day*100 + hourOfDay), nil
+	default:
+		return 0, types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, unit)
+	}
 }
 
 func (b *builtinStrToDateDurationSig) vectorized() bool {
@@ -1113,25 +1393,92 @@ func (b *builtinToSecondsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 			continue
 		}
 		arg := ds[i]
-		ret := types.TimestampDiff("SECOND", types.ZeroDate, arg)
-		if ret == 0 {
+		if arg.IsZero() {
 			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, arg.String())); err != nil {
 				return err
 			}
 			result.SetNull(i, true)
 			continue
 		}
-		i64s[i] = ret
+		// Computed directly from the day-number formula instead of via
+		// TimestampDiff against ZeroDate, avoiding a full calendar diff per row.
+		i64s[i] = arg.ToSeconds()
 	}
 	return nil
 }
 
 func (b *builtinSubDurationAndStringSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalDuration evals a builtinSubDurationAndStringSig, i.e. SUBTIME(duration, str).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_subtime
 func (b *builtinSubDurationAndStringSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err = b.args[0].VecEvalDuration(b.ctx, input, buf0); err != nil {
+		return err
+	}
+
+	buf1, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err = b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	result.ResizeGoDuration(n, false)
+	d64s := result.GoDurations()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) || buf1.IsNull(i) {
+			result.SetNull(i, true)
+			continue
+		}
+
+		s := buf1.GetString(i)
+		arg1, err := types.ParseDuration(sc, s, getFsp4TimeAddSub(s))
+		if err != nil {
+			if terror.ErrorEqual(err, types.ErrTruncatedWrongVal) {
+				sc.AppendWarning(err)
+				result.SetNull(i, true)
+				continue
+			}
+			return err
+		}
+		arg0 := buf0.GetDuration(i, 0)
+		res, isNull, err := buildinSubDurationAndDuration(sc, arg0, arg1)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		d64s[i] = res.Duration
+	}
+	return nil
+}
+
+// buildinSubDurationAndDuration subtracts arg1 from arg0, truncating the
+// result to the valid MySQL TIME range the same way the scalar SUBTIME path
+// does.
+func buildinSubDurationAndDuration(sc *stmtctx.StatementContext, arg0, arg1 types.Duration) (types.Duration, bool, error) {
+	res, err := arg0.Sub(arg1)
+	if err != nil {
+		if terror.ErrorEqual(err, types.ErrTruncatedWrongVal) {
+			sc.AppendWarning(err)
+			return types.Duration{}, true, nil
+		}
+		return types.Duration{}, false, err
+	}
+	return res, false, nil
 }
 
 func (b *builtinSubDateAndStringSig) vectorized() bool {
@@ -1413,11 +1760,9 @@ func (b *builtinTimeToSecSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 		if result.IsNull(i) {
 			continue
 		}
-		var sign int
+		sign := 1
 		duration := buf.GetDuration(i, int(fsp))
-		if duration.Duration >= 0 {
-			sign = 1
-		} else {
+		if duration.IsNeg() {
 			sign = -1
 		}
 		i64s[i] = int64(sign * (duration.Hour()*3600 + duration.Minute()*60 + duration.Second()))
@@ -1637,6 +1982,12 @@ func (b *builtinTimestampAddSig) vecEvalString(input *chunk.Chunk, result *chunk
 	result.ReserveString(n)
 	nums := buf1.Int64s()
 	ds := buf2.Times()
+	// TIMESTAMPADD's unit argument is written as a bare keyword in SQL, so
+	// in practice it's the same value on every row of a batch; cache the
+	// last resolved IntervalExpression and only re-resolve (re-switch on
+	// the unit string) when a row's unit actually differs from it, instead
+	// of unconditionally re-switching on every row.
+	var ie *IntervalExpression
 	for i := 0; i < n; i++ {
 		if buf.IsNull(i) || buf1.IsNull(i) || buf2.IsNull(i) {
 			result.AppendNull()
@@ -1647,36 +1998,23 @@ func (b *builtinTimestampAddSig) vecEvalString(input *chunk.Chunk, result *chunk
 		v := nums[i]
 		arg := ds[i]
 
-		tm1, err := arg.Time.GoTime(time.Local)
-		if err != nil {
-			return err
+		if ie == nil || ie.Unit != unit {
+			var err error
+			ie, err = NewIntervalExpression(unit)
+			if err != nil {
+				return err
+			}
 		}
-		var tb time.Time
+		iv := ie.Eval(v)
 		fsp := types.DefaultFsp
-		switch unit {
-		case "MICROSECOND":
-			tb = tm1.Add(time.Duration(v) * time.Microsecond)
+		if unit == "MICROSECOND" {
 			fsp = types.MaxFsp
-		case "SECOND":
-			tb = tm1.Add(time.Duration(v) * time.Second)
-		case "MINUTE":
-			tb = tm1.Add(time.Duration(v) * time.Minute)
-		case "HOUR":
-			tb = tm1.Add(time.Duration(v) * time.Hour)
-		case "DAY":
-			tb = tm1.AddDate(0, 0, int(v))
-		case "WEEK":
-			tb = tm1.AddDate(0, 0, 7*int(v))
-		case "MONTH":
-			tb = tm1.AddDate(0, int(v), 0)
-		case "QUARTER":
-			tb = tm1.AddDate(0, 3*int(v), 0)
-		case "YEAR":
-			tb = tm1.AddDate(int(v), 0, 0)
-		default:
-			return types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, unit)
-		}
-		r := types.Time{Time: types.FromGoTime(tb), Type: b.resolveType(arg.Type, unit), Fsp: fsp}
+		}
+		arg.Type, arg.Fsp = b.resolveType(arg.Type, unit), fsp
+		r, err := arg.AddInterval(iv)
+		if err != nil {
+			return err
+		}
 		if err = r.Check(b.ctx.GetSessionVars().StmtCtx); err != nil {
 			if err = handleInvalidTimeError(b.ctx, err); err != nil {
 				return err
@@ -1715,27 +2053,20 @@ func (b *builtinToDaysSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 			continue
 		}
 		arg := ds[i]
-		ret := types.TimestampDiff("DAY", types.ZeroDate, arg)
-		if ret == 0 {
+		if arg.IsZero() {
 			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, arg.String())); err != nil {
 				return err
 			}
 			result.SetNull(i, true)
 			continue
 		}
-		i64s[i] = ret
+		// types.MysqlDayNumber is the same day-number formula TimestampDiff
+		// used internally, called directly to skip the generic diff machinery.
+		i64s[i] = types.MysqlDayNumber(arg.Time.Year(), int(arg.Time.Month()), arg.Time.Day())
 	}
 	return nil
 }
 
-func (b *builtinDateFormatSig) vectorized() bool {
-	return false
-}
-
-func (b *builtinDateFormatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
-}
-
 func (b *builtinHourSig) vectorized() bool {
 	return true
 }
@@ -1787,25 +2118,19 @@ func (b *builtinSecToTimeSig) vecEvalDuration(input *chunk.Chunk, result *chunk.
 			continue
 		}
 		secondsFloat := args[i]
-		negative := ""
-		if secondsFloat < 0 {
-			negative = "-"
+		neg := secondsFloat < 0
+		if neg {
 			secondsFloat = math.Abs(secondsFloat)
 		}
 		seconds := int64(secondsFloat)
-		demical := secondsFloat - float64(seconds)
-		var minute, second int64
+		microsecond := int((secondsFloat - float64(seconds)) * float64(time.Second/time.Microsecond))
 		hour := seconds / 3600
-		if hour > 838 {
-			hour = 838
-			minute = 59
-			second = 59
-		} else {
-			minute = seconds % 3600 / 60
-			second = seconds % 60
-		}
-		secondDemical := float64(second) + demical
-		duration, err := types.ParseDuration(b.ctx.GetSessionVars().StmtCtx, fmt.Sprintf("%s%02d:%02d:%v", negative, hour, minute, secondDemical), int8(b.tp.Decimal))
+		minute := seconds % 3600 / 60
+		second := seconds % 60
+		// NewDurationFromParts assigns the sign and fields directly, so a
+		// fractional second that is negative but rounds to zero whole
+		// seconds (e.g. SEC_TO_TIME(-0.5)) keeps its sign.
+		duration, err := types.NewDurationFromParts(neg, hour, minute, second, microsecond, int8(b.tp.Decimal))
 		if err != nil {
 			return err
 		}
@@ -1945,17 +2270,14 @@ func (b *builtinDateDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 			continue
 		}
 		if invalidArg0, invalidArg1 := args0[i].InvalidZero(), args1[i].InvalidZero(); invalidArg0 || invalidArg1 {
-			if invalidArg0 {
-				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, args0[i].String()))
-			}
-			if invalidArg1 {
-				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, args1[i].String()))
-			}
+			isNull, err := zeroOrInvalidTimeIsNullForArgs(b.ctx, invalidArg0, args0[i], invalidArg1, args1[i])
 			if err != nil {
 				return err
 			}
-			result.SetNull(i, true)
-			continue
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
 		}
 		i64s[i] = int64(types.DateDiff(args0[i].Time, args1[i].Time))
 	}
@@ -1989,19 +2311,120 @@ func (b *builtinCurrentDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.Co
 }
 
 func (b *builtinMakeTimeSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalDuration evals a builtinMakeTimeSig, i.e. MAKETIME(hour, minute, second).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_maketime
 func (b *builtinMakeTimeSig) vecEvalDuration(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	bufHour, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufHour)
+	if err := b.args[0].VecEvalInt(b.ctx, input, bufHour); err != nil {
+		return err
+	}
+
+	bufMinute, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufMinute)
+	if err := b.args[1].VecEvalInt(b.ctx, input, bufMinute); err != nil {
+		return err
+	}
+
+	bufSecond, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufSecond)
+	if err := b.args[2].VecEvalReal(b.ctx, input, bufSecond); err != nil {
+		return err
+	}
+
+	result.ResizeGoDuration(n, false)
+	result.MergeNulls(bufHour, bufMinute, bufSecond)
+	d64s := result.GoDurations()
+	hours := bufHour.Int64s()
+	minutes := bufMinute.Int64s()
+	seconds := bufSecond.Float64s()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	fsp := int8(b.tp.Decimal)
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		hour, minute, second := hours[i], minutes[i], seconds[i]
+		if minute < 0 || minute > 59 || second < 0 || second >= 60 {
+			sc.AppendWarning(types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, "MAKETIME"))
+			result.SetNull(i, true)
+			continue
+		}
+		neg := hour < 0
+		if neg {
+			hour = -hour
+		}
+		wholeSecond := int64(second)
+		microsecond := int(math.Round((second - float64(wholeSecond)) * float64(time.Second/time.Microsecond)))
+		if hour > types.TimeMaxHour {
+			sc.AppendWarning(types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, "MAKETIME"))
+			hour, minute, wholeSecond, microsecond = types.TimeMaxHour, types.TimeMaxMinute, types.TimeMaxSecond, 0
+		}
+		duration, err := types.NewDurationFromParts(neg, hour, minute, wholeSecond, microsecond, fsp)
+		if err != nil {
+			return err
+		}
+		d64s[i] = duration.Duration
+	}
+	return nil
 }
 
 func (b *builtinSubDateAndDurationSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalString evals a builtinSubDateAndDurationSig, i.e. SUBDATE(date, duration).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-sub
 func (b *builtinSubDateAndDurationSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err := b.args[0].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+
+	bufDur, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDur)
+	if err := b.args[1].VecEvalDuration(b.ctx, input, bufDur); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	ds := bufTime.Times()
+	for i := 0; i < n; i++ {
+		if bufTime.IsNull(i) || bufDur.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		dur := bufDur.GetDuration(i, 0)
+		neg := types.Duration{Duration: -dur.Duration, Fsp: dur.Fsp}
+		res, err := ds[i].Add(sc, neg)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res.String())
+	}
+	return nil
 }
 
 func (b *builtinDayOfYearSig) vectorized() bool {
@@ -2024,15 +2447,23 @@ func (b *builtinDayOfYearSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 	result.MergeNulls(buf)
 	i64s := result.Int64s()
 	ds := buf.Times()
+	extended := extendedDateRangeEnabled(b.ctx)
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
 			continue
 		}
 		if ds[i].InvalidZero() {
-			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
+			isNull, err := zeroOrInvalidTimeIsNull(b.ctx, ds[i])
+			if err != nil {
 				return err
 			}
-			result.SetNull(i, true)
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
+		}
+		if extended && ds[i].Time.Year() < 1 {
+			i64s[i] = int64(types.YearDayProleptic(ds[i]))
 			continue
 		}
 		i64s[i] = int64(ds[i].Time.YearDay())
@@ -2104,23 +2535,32 @@ func (b *builtinYearWeekWithModeSig) vecEvalInt(input *chunk.Chunk, result *chun
 	i64s := result.Int64s()
 	ds := buf1.Times()
 	ms := buf2.Int64s()
+	extended := extendedDateRangeEnabled(b.ctx)
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
 			continue
 		}
 		date := ds[i]
 		if date.IsZero() {
-			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, date.String())); err != nil {
+			isNull, err := zeroOrInvalidTimeIsNull(b.ctx, date)
+			if err != nil {
 				return err
 			}
-			result.SetNull(i, true)
-			continue
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
 		}
 		mode := int(ms[i])
 		if buf2.IsNull(i) {
 			mode = 0
 		}
-		year, week := date.Time.YearWeek(mode)
+		var year, week int
+		if extended && date.Time.Year() < 1 {
+			year, week = types.YearWeekProleptic(date, mode)
+		} else {
+			year, week = date.Time.YearWeek(mode)
+		}
 		i64s[i] = int64(week + year*100)
 		if i64s[i] < 0 {
 			i64s[i] = int64(math.MaxUint32)
@@ -2172,17 +2612,14 @@ func (b *builtinTimestampDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.C
 			continue
 		}
 		if invalidLHS, invalidRHS := lhs[i].InvalidZero(), rhs[i].InvalidZero(); invalidLHS || invalidRHS {
-			if invalidLHS {
-				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, lhs[i].String()))
-			}
-			if invalidRHS {
-				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, rhs[i].String()))
-			}
+			isNull, err := zeroOrInvalidTimeIsNullForArgs(b.ctx, invalidLHS, lhs[i], invalidRHS, rhs[i])
 			if err != nil {
 				return err
 			}
-			result.SetNull(i, true)
-			continue
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
 		}
 		i64s[i] = types.TimestampDiff(unitBuf.GetString(i), lhs[i], rhs[i])
 	}
@@ -2190,11 +2627,51 @@ func (b *builtinTimestampDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.C
 }
 
 func (b *builtinUnixTimestampIntSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalInt evals a builtinUnixTimestampIntSig, i.e. UNIX_TIMESTAMP(datetime)
+// truncated (not rounded) to whole seconds.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
 func (b *builtinUnixTimestampIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.Times()
+	loc := b.ctx.GetSessionVars().Location()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if ds[i].IsZero() {
+			i64s[i] = 0
+			continue
+		}
+		t, err := ds[i].Time.GoTime(loc)
+		if err != nil {
+			return err
+		}
+		dec, err := goTimeToMysqlUnixTimestamp(t, 0)
+		if err != nil {
+			return err
+		}
+		intVal, err := dec.ToInt()
+		if err != nil && !terror.ErrorEqual(err, types.ErrTruncated) {
+			return err
+		}
+		i64s[i] = intVal
+	}
+	return nil
 }
 
 func (b *builtinCurrentTime0ArgSig) vectorized() bool {
@@ -2331,13 +2808,18 @@ func (b *builtinMonthNameSig) vecEvalString(input *chunk.Chunk, result *chunk.Co
 
 	result.ReserveString(n)
 	ds := buf.Times()
+	names := sessionLocaleNames(b.ctx)
 	for i := 0; i < n; i++ {
 		if buf.IsNull(i) {
 			result.AppendNull()
 			continue
 		}
 		mon := ds[i].Time.Month()
-		if (ds[i].IsZero() && b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode()) || mon < 0 || mon > len(types.MonthNames) {
+		if ds[i].IsZero() && zeroDateAsNullEnabled(b.ctx) {
+			result.AppendNull()
+			continue
+		}
+		if (ds[i].IsZero() && b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode()) || mon < 0 || mon > len(names.MonthNames) {
 			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
 				return err
 			}
@@ -2347,7 +2829,7 @@ func (b *builtinMonthNameSig) vecEvalString(input *chunk.Chunk, result *chunk.Co
 			result.AppendNull()
 			continue
 		}
-		result.AppendString(types.MonthNames[mon-1])
+		result.AppendString(names.MonthNames[mon-1])
 	}
 	return nil
 }
@@ -2357,11 +2839,48 @@ func (b *builtinMonthNameSig) vectorized() bool {
 }
 
 func (b *builtinSubDatetimeAndDurationSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalTime evals a builtinSubDatetimeAndDurationSig, i.e. datetime - duration.
 func (b *builtinSubDatetimeAndDurationSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err := b.args[0].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+
+	bufDur, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufDur)
+	if err := b.args[1].VecEvalDuration(b.ctx, input, bufDur); err != nil {
+		return err
+	}
+
+	result.ResizeTime(n, false)
+	result.MergeNulls(bufTime, bufDur)
+	times := result.Times()
+	ds := bufTime.Times()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		dur := bufDur.GetDuration(i, 0)
+		neg := types.Duration{Duration: -dur.Duration, Fsp: dur.Fsp}
+		res, err := ds[i].Add(sc, neg)
+		if err != nil {
+			return err
+		}
+		times[i] = res
+	}
+	return nil
 }
 
 func (b *builtinDayOfWeekSig) vectorized() bool {
@@ -2382,15 +2901,23 @@ func (b *builtinDayOfWeekSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 	result.MergeNulls(buf)
 	i64s := result.Int64s()
 	ds := buf.Times()
+	extended := extendedDateRangeEnabled(b.ctx)
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
 			continue
 		}
 		if ds[i].InvalidZero() {
-			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
+			isNull, err := zeroOrInvalidTimeIsNull(b.ctx, ds[i])
+			if err != nil {
 				return err
 			}
-			result.SetNull(i, true)
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
+		}
+		if extended && ds[i].Time.Year() < 1 {
+			i64s[i] = int64(types.WeekdayProleptic(ds[i]) + 1)
 			continue
 		}
 		i64s[i] = int64(ds[i].Time.Weekday() + 1)
@@ -2458,11 +2985,80 @@ func (b *builtinUTCTimestampWithoutArgSig) vecEvalTime(input *chunk.Chunk, resul
 }
 
 func (b *builtinConvertTzSig) vectorized() bool {
-	return false
+	return true
 }
 
+// vecEvalTime evals a builtinConvertTzSig, i.e. CONVERT_TZ(dt, from_tz, to_tz).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_convert-tz
 func (b *builtinConvertTzSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
-	return errors.Errorf("not implemented")
+	n := input.NumRows()
+	bufTime, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTime)
+	if err := b.args[0].VecEvalTime(b.ctx, input, bufTime); err != nil {
+		return err
+	}
+
+	bufFrom, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufFrom)
+	if err := b.args[1].VecEvalString(b.ctx, input, bufFrom); err != nil {
+		return err
+	}
+
+	bufTo, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(bufTo)
+	if err := b.args[2].VecEvalString(b.ctx, input, bufTo); err != nil {
+		return err
+	}
+
+	result.ResizeTime(n, false)
+	result.MergeNulls(bufTime, bufFrom, bufTo)
+	times := result.Times()
+	ds := bufTime.Times()
+	// Caches *time.Location lookups across rows so repeated zone names (the
+	// common case: from_tz/to_tz are usually literals) only pay for
+	// time.LoadLocation once.
+	locCache := make(map[string]*time.Location, 2)
+	loadLoc := func(name string) (*time.Location, error) {
+		if loc, ok := locCache[name]; ok {
+			return loc, nil
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, err
+		}
+		locCache[name] = loc
+		return loc, nil
+	}
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		fromLoc, err := loadLoc(bufFrom.GetString(i))
+		if err != nil {
+			result.SetNull(i, true)
+			continue
+		}
+		toLoc, err := loadLoc(bufTo.GetString(i))
+		if err != nil {
+			result.SetNull(i, true)
+			continue
+		}
+		t := ds[i]
+		if err := t.ConvertTimeZone(fromLoc, toLoc); err != nil {
+			return err
+		}
+		times[i] = t
+	}
+	return nil
 }
 
 func (b *builtinTimestamp1ArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
@@ -2480,6 +3076,7 @@ func (b *builtinTimestamp1ArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.
 	result.MergeNulls(buf)
 	times := result.Times()
 	sc := b.ctx.GetSessionVars().StmtCtx
+	extended := extendedDateRangeEnabled(b.ctx)
 	var tm types.Time
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
@@ -2489,6 +3086,8 @@ func (b *builtinTimestamp1ArgSig) vecEvalTime(input *chunk.Chunk, result *chunk.
 
 		if b.isFloat {
 			tm, err = types.ParseTimeFromFloatString(sc, s, mysql.TypeDatetime, types.GetFsp(s))
+		} else if extended {
+			tm, err = types.ParseTimeExtended(sc, s, mysql.TypeDatetime, types.GetFsp(s))
 		} else {
 			tm, err = types.ParseTime(sc, s, mysql.TypeDatetime, types.GetFsp(s))
 		}
@@ -2532,6 +3131,7 @@ func (b *builtinTimestamp2ArgsSig) vecEvalTime(input *chunk.Chunk, result *chunk
 	result.MergeNulls(buf0, buf1)
 	times := result.Times()
 	sc := b.ctx.GetSessionVars().StmtCtx
+	extended := extendedDateRangeEnabled(b.ctx)
 	var tm types.Time
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
@@ -2542,6 +3142,8 @@ func (b *builtinTimestamp2ArgsSig) vecEvalTime(input *chunk.Chunk, result *chunk
 
 		if b.isFloat {
 			tm, err = types.ParseTimeFromFloatString(sc, arg0, mysql.TypeDatetime, types.GetFsp(arg0))
+		} else if extended {
+			tm, err = types.ParseTimeExtended(sc, arg0, mysql.TypeDatetime, types.GetFsp(arg0))
 		} else {
 			tm, err = types.ParseTime(sc, arg0, mysql.TypeDatetime, types.GetFsp(arg0))
 		}
@@ -2598,10 +3200,11 @@ func (b *builtinDayOfMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colu
 			continue
 		}
 		if ds[i].IsZero() {
-			if b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode() {
-				if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, ds[i].String())); err != nil {
-					return err
-				}
+			isNull, err := zeroDateFallback(b.ctx, ds[i])
+			if err != nil {
+				return err
+			}
+			if isNull {
 				result.SetNull(i, true)
 				continue
 			}
@@ -0,0 +1,68 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import "github.com/pingcap/tidb/types"
+
+// IntervalExpression pre-resolves a MySQL interval unit keyword (as
+// DATE_ADD/DATE_SUB/TIMESTAMPADD accept) into the types.Interval field
+// setter it maps to, once, so a vectorized row loop applies that setter
+// directly to each row's magnitude instead of re-switching on the unit
+// string inside types.NewIntervalFromUnit for every row. Building one is
+// the single place that switch should happen for a batch of rows sharing
+// a unit; everything after that is a func call.
+type IntervalExpression struct {
+	// Unit is the keyword this IntervalExpression was resolved from (e.g.
+	// "DAY", "MICROSECOND"), kept so a caller can cheaply tell whether a
+	// newly observed unit value still matches the one already resolved.
+	Unit string
+
+	apply func(v int64) types.Interval
+}
+
+// NewIntervalExpression resolves unit's field-setter once. It accepts the
+// same unit set and returns the same error as types.NewIntervalFromUnit;
+// the difference is purely when the unit switch runs; the returned
+// IntervalExpression's Eval does no branching on unit at all.
+func NewIntervalExpression(unit string) (*IntervalExpression, error) {
+	var apply func(v int64) types.Interval
+	switch unit {
+	case "MICROSECOND":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Micros: v} }
+	case "SECOND":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Secs: v} }
+	case "MINUTE":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Mins: v} }
+	case "HOUR":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Hours: v} }
+	case "DAY":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Days: v} }
+	case "WEEK":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Days: v * 7} }
+	case "MONTH":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Months: v} }
+	case "QUARTER":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Months: v * 3} }
+	case "YEAR":
+		apply = func(v int64) types.Interval { return types.Interval{Unit: unit, Years: v} }
+	default:
+		return nil, types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, unit)
+	}
+	return &IntervalExpression{Unit: unit, apply: apply}, nil
+}
+
+// Eval applies ie's pre-resolved field setter to magnitude v.
+func (ie *IntervalExpression) Eval(v int64) types.Interval {
+	return ie.apply(v)
+}
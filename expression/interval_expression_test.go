@@ -0,0 +1,56 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/types"
+)
+
+// TestNewIntervalExpressionMatchesNewIntervalFromUnit guards against the
+// two unit-resolution paths drifting apart: whatever IntervalExpression
+// resolves for a unit/magnitude must equal what the original per-row
+// types.NewIntervalFromUnit would have produced.
+func TestNewIntervalExpressionMatchesNewIntervalFromUnit(t *testing.T) {
+	units := []string{"MICROSECOND", "SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR"}
+	for _, unit := range units {
+		ie, err := NewIntervalExpression(unit)
+		if err != nil {
+			t.Fatalf("NewIntervalExpression(%q): %v", unit, err)
+		}
+		if ie.Unit != unit {
+			t.Errorf("Unit = %q, want %q", ie.Unit, unit)
+		}
+		for _, v := range []int64{0, 1, -3, 42} {
+			got := ie.Eval(v)
+			want, err := types.NewIntervalFromUnit(v, unit)
+			if err != nil {
+				t.Fatalf("types.NewIntervalFromUnit(%d, %q): %v", v, unit, err)
+			}
+			if got != want {
+				t.Errorf("IntervalExpression(%q).Eval(%d) = %+v, want %+v", unit, v, got, want)
+			}
+		}
+	}
+}
+
+// TestNewIntervalExpressionUnknownUnit guards the error path: an
+// unrecognized unit must fail the same way types.NewIntervalFromUnit does,
+// not silently resolve to a zero Interval.
+func TestNewIntervalExpressionUnknownUnit(t *testing.T) {
+	if _, err := NewIntervalExpression("NOT_A_UNIT"); err == nil {
+		t.Fatalf("NewIntervalExpression(\"NOT_A_UNIT\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/infoschema/inspection"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// TableClusterConfig is the name INFORMATION_SCHEMA.CLUSTER_CONFIG is
+// registered under.
+const TableClusterConfig = "CLUSTER_CONFIG"
+
+// clusterConfigCols is CLUSTER_CONFIG's column set: one (type, instance,
+// key, value) row per config leaf, matching the row shape
+// fetchClusterConfig already returns.
+var clusterConfigCols = []columnInfo{
+	{name: "TYPE", typeName: "varchar(64)"},
+	{name: "INSTANCE", typeName: "varchar(64)"},
+	{name: "KEY", typeName: "varchar(256)"},
+	{name: "VALUE", typeName: "varchar(1024)"},
+}
+
+// clusterInstances is how dataForClusterConfig learns the live instance
+// list (every PD, TiDB and TiKV the cluster currently has) to fan the
+// "/config" GET out to. Nothing in this package assigns it, and no
+// instance discovery (PD's member list, PD's store list, this TiDB's own
+// server list) is implemented here; while it is nil, CLUSTER_CONFIG (and
+// CLUSTER_INFO) simply return no rows instead of erroring, the same way a
+// rule in package inspection reports nothing when its hook isn't wired.
+var clusterInstances func(ctx context.Context) ([]clusterConfigInstance, error)
+
+// dataForClusterConfig is CLUSTER_CONFIG's row source: it resolves the
+// live instance list via clusterInstances and flattens each instance's
+// "/config" response with fetchClusterConfig.
+func dataForClusterConfig(ctx context.Context, typeFilter, instanceFilter func(string) bool) ([][]string, error) {
+	if clusterInstances == nil {
+		return nil, nil
+	}
+	instances, err := clusterInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fetchClusterConfig(ctx, instances, "/config", typeFilter, instanceFilter), nil
+}
+
+func init() {
+	registerVirtualTable(TableClusterConfig, clusterConfigCols, dataForClusterConfig)
+	inspection.ClusterConfigs = func(ctx sessionctx.Context) ([]inspection.ConfigInstance, error) {
+		rows, err := dataForClusterConfig(context.Background(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		byInstance := map[string]*inspection.ConfigInstance{}
+		var order []string
+		for _, row := range rows {
+			serverType, addr, key, value := row[0], row[1], row[2], row[3]
+			inst, ok := byInstance[addr]
+			if !ok {
+				inst = &inspection.ConfigInstance{ServerType: serverType, Address: addr, Config: map[string]string{}}
+				byInstance[addr] = inst
+				order = append(order, addr)
+			}
+			inst.Config[key] = value
+		}
+		out := make([]inspection.ConfigInstance, 0, len(order))
+		for _, addr := range order {
+			out = append(out, *byInstance[addr])
+		}
+		return out, nil
+	}
+}
+
+// clusterConfigInstance identifies one component instance CLUSTER_CONFIG
+// fans a "/config" GET out to. ServerType is "tidb", "tikv" or "pd";
+// StatusAddr is the host:port the component's HTTP status/config endpoint
+// listens on, which is not always the same as Address (the component's
+// client-facing address). Discovering the live instance list itself (PD
+// members, PD's store list, and this TiDB cluster's own server list) is
+// not done here; this file only fetches and flattens each instance's
+// config for the list clusterInstances returns.
+type clusterConfigInstance struct {
+	ServerType string
+	Address    string
+	StatusAddr string
+}
+
+// clusterConfigFetchTimeout bounds a single instance's config GET so one
+// slow or wedged node can't hang the whole CLUSTER_CONFIG query.
+const clusterConfigFetchTimeout = 10 * time.Second
+
+// fetchClusterConfig issues one HTTP GET per instance (in parallel) against
+// path, flattens the returned JSON object, and returns one
+// (type, instance, key, value) row per leaf. TYPE and INSTANCE predicates
+// are applied against instances before any request is made, so a query
+// scoped to one component or host only contacts the hosts it needs.
+//
+// A fetch error (timeout, non-200, malformed JSON) doesn't abort the whole
+// query: it surfaces as a single row for that instance whose VALUE carries
+// the error, keeping the table's column shape (TYPE, INSTANCE, KEY, VALUE)
+// uniform whether or not every node answered.
+func fetchClusterConfig(ctx context.Context, instances []clusterConfigInstance, path string, typeFilter, instanceFilter func(string) bool) [][]string {
+	type fetchResult struct {
+		instance clusterConfigInstance
+		rows     [][2]string // key, value pairs
+		err      error
+	}
+
+	var wanted []clusterConfigInstance
+	for _, inst := range instances {
+		if typeFilter != nil && !typeFilter(inst.ServerType) {
+			continue
+		}
+		if instanceFilter != nil && !instanceFilter(inst.Address) {
+			continue
+		}
+		wanted = append(wanted, inst)
+	}
+
+	results := make(chan fetchResult, len(wanted))
+	for _, inst := range wanted {
+		go func(inst clusterConfigInstance) {
+			rows, err := fetchAndFlattenConfig(ctx, inst.StatusAddr, path)
+			results <- fetchResult{instance: inst, rows: rows, err: err}
+		}(inst)
+	}
+
+	var out [][]string
+	for range wanted {
+		res := <-results
+		if res.err != nil {
+			out = append(out, []string{res.instance.ServerType, res.instance.Address, "", fmt.Sprintf("error: %v", res.err)})
+			continue
+		}
+		for _, kv := range res.rows {
+			out = append(out, []string{res.instance.ServerType, res.instance.Address, kv[0], kv[1]})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i][0] != out[j][0] {
+			return out[i][0] < out[j][0]
+		}
+		if out[i][1] != out[j][1] {
+			return out[i][1] < out[j][1]
+		}
+		return out[i][2] < out[j][2]
+	})
+	return out
+}
+
+// fetchAndFlattenConfig GETs path off statusAddr and flattens the resulting
+// JSON object into dot-joined leaf key/value pairs, e.g. the nested
+// {"key3": {"key4": {"nest3": "v"}}} becomes ("key3.key4.nest3", "v").
+func fetchAndFlattenConfig(ctx context.Context, statusAddr, path string) ([][2]string, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, clusterConfigFetchTimeout)
+	defer cancel()
+
+	url := "http://" + statusAddr + path
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+
+	var config map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, err
+	}
+
+	var rows [][2]string
+	flattenConfig("", config, &rows)
+	return rows, nil
+}
+
+// flattenConfig walks config depth-first, appending a (dot-joined-key,
+// string-value) pair to rows for every leaf (non-map) value it finds.
+func flattenConfig(prefix string, config map[string]interface{}, rows *[][2]string) {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fullKey := k
+		if prefix != "" {
+			fullKey = prefix + "." + k
+		}
+		switch v := config[k].(type) {
+		case map[string]interface{}:
+			flattenConfig(fullKey, v, rows)
+		case map[string]string:
+			nested := make(map[string]interface{}, len(v))
+			for nk, nv := range v {
+				nested[nk] = nv
+			}
+			flattenConfig(fullKey, nested, rows)
+		default:
+			*rows = append(*rows, [2]string{fullKey, fmt.Sprint(v)})
+		}
+	}
+}
+
+// clusterConfigPredicate builds a case-insensitive exact-match predicate
+// for the TYPE/INSTANCE pushdown fetchClusterConfig accepts, from the
+// literal values a `WHERE type = '...'`/`WHERE instance = '...'` extracts.
+// A nil want means no predicate is pushed down.
+func clusterConfigPredicate(want []string) func(string) bool {
+	if len(want) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(want))
+	for _, w := range want {
+		set[strings.ToLower(w)] = struct{}{}
+	}
+	return func(v string) bool {
+		_, ok := set[strings.ToLower(v)]
+		return ok
+	}
+}
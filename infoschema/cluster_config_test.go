@@ -0,0 +1,113 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestFlattenConfigDotJoinsNestedKeys guards flattenConfig's recursive
+// descent: every leaf, however deeply nested, must come out as one
+// dot-joined key with its own row.
+func TestFlattenConfigDotJoinsNestedKeys(t *testing.T) {
+	config := map[string]interface{}{
+		"key1": "v1",
+		"key3": map[string]interface{}{
+			"key4": map[string]interface{}{
+				"nest3": "v",
+			},
+		},
+	}
+	var rows [][2]string
+	flattenConfig("", config, &rows)
+
+	got := map[string]string{}
+	for _, kv := range rows {
+		got[kv[0]] = kv[1]
+	}
+	want := map[string]string{
+		"key1":            "v1",
+		"key3.key4.nest3": "v",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("flattened[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d leaves, want %d: %+v", len(got), len(want), got)
+	}
+}
+
+// TestFetchClusterConfigAppliesFiltersAndSurfacesErrors exercises
+// fetchClusterConfig end to end: a TYPE/INSTANCE predicate should drop
+// instances before any request is made, and a request that fails (here,
+// a non-200) should surface as one VALUE="error: ..." row rather than
+// aborting the whole query.
+func TestFetchClusterConfigAppliesFiltersAndSurfacesErrors(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"a": "1"}`))
+	}))
+	defer ok.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	instances := []clusterConfigInstance{
+		{ServerType: "tidb", Address: "tidb-1", StatusAddr: strings.TrimPrefix(ok.URL, "http://")},
+		{ServerType: "tikv", Address: "tikv-1", StatusAddr: strings.TrimPrefix(bad.URL, "http://")},
+		{ServerType: "pd", Address: "pd-1", StatusAddr: strings.TrimPrefix(ok.URL, "http://")},
+	}
+	typeFilter := clusterConfigPredicate([]string{"tidb", "tikv"})
+
+	rows := fetchClusterConfig(context.Background(), instances, "/config", typeFilter, nil)
+
+	byAddr := map[string][]string{}
+	for _, row := range rows {
+		byAddr[row[1]] = row
+	}
+	if _, ok := byAddr["pd-1"]; ok {
+		t.Errorf("pd-1 should have been filtered out by TYPE predicate, got row %+v", byAddr["pd-1"])
+	}
+	if row, ok := byAddr["tidb-1"]; !ok || row[2] != "a" || row[3] != "1" {
+		t.Errorf("tidb-1 row = %+v, want key=a value=1", row)
+	}
+	row, ok := byAddr["tikv-1"]
+	if !ok {
+		t.Fatalf("tikv-1 missing from rows")
+	}
+	if !strings.HasPrefix(row[3], "error: ") {
+		t.Errorf("tikv-1 VALUE = %q, want it to surface the fetch error", row[3])
+	}
+}
+
+// TestClusterConfigPredicateCaseInsensitive guards the predicate builder's
+// documented case-insensitive matching.
+func TestClusterConfigPredicateCaseInsensitive(t *testing.T) {
+	if p := clusterConfigPredicate(nil); p != nil {
+		t.Errorf("clusterConfigPredicate(nil) = %v, want nil (no predicate)", p)
+	}
+	p := clusterConfigPredicate([]string{"TiDB"})
+	if !p("tidb") || !p("TIDB") {
+		t.Errorf("predicate should match case-insensitively")
+	}
+	if p("tikv") {
+		t.Errorf("predicate matched an address not in the want list")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/pingcap/tidb/infoschema/inspection"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// TableClusterInfo is the name INFORMATION_SCHEMA.CLUSTER_INFO is
+// registered under.
+const TableClusterInfo = "CLUSTER_INFO"
+
+var clusterInfoCols = []columnInfo{
+	{name: "TYPE", typeName: "varchar(64)"},
+	{name: "INSTANCE", typeName: "varchar(64)"},
+	{name: "VERSION", typeName: "varchar(64)"},
+}
+
+// dataForClusterInfo is CLUSTER_INFO's row source: it resolves the live
+// instance list via clusterInstances (the same discovery CLUSTER_CONFIG
+// uses) and GETs each instance's "/status" endpoint for its reported
+// build version.
+func dataForClusterInfo(ctx context.Context) ([][]string, error) {
+	if clusterInstances == nil {
+		return nil, nil
+	}
+	instances, err := clusterInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fetchClusterVersions(ctx, instances), nil
+}
+
+// fetchClusterVersions issues one "/status" GET per instance (in
+// parallel) and extracts its "version" field. A fetch error (timeout,
+// non-200, malformed JSON, or a missing "version" key) doesn't abort the
+// whole query: it surfaces as a row whose VERSION carries the error,
+// keeping CLUSTER_INFO's column shape uniform whether or not every node
+// answered.
+func fetchClusterVersions(ctx context.Context, instances []clusterConfigInstance) [][]string {
+	type fetchResult struct {
+		instance clusterConfigInstance
+		version  string
+		err      error
+	}
+
+	results := make(chan fetchResult, len(instances))
+	for _, inst := range instances {
+		go func(inst clusterConfigInstance) {
+			version, err := fetchStatusVersion(ctx, inst.StatusAddr)
+			results <- fetchResult{instance: inst, version: version, err: err}
+		}(inst)
+	}
+
+	out := make([][]string, 0, len(instances))
+	for range instances {
+		res := <-results
+		version := res.version
+		if res.err != nil {
+			version = fmt.Sprintf("error: %v", res.err)
+		}
+		out = append(out, []string{res.instance.ServerType, res.instance.Address, version})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i][0] != out[j][0] {
+			return out[i][0] < out[j][0]
+		}
+		return out[i][1] < out[j][1]
+	})
+	return out
+}
+
+// fetchStatusVersion GETs statusAddr's "/status" endpoint and returns its
+// "version" field.
+func fetchStatusVersion(ctx context.Context, statusAddr string) (string, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, clusterConfigFetchTimeout)
+	defer cancel()
+
+	url := "http://" + statusAddr + "/status"
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+
+	var status struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return "", err
+	}
+	if status.Version == "" {
+		return "", fmt.Errorf("GET %s: response had no version field", url)
+	}
+	return status.Version, nil
+}
+
+func init() {
+	registerVirtualTable(TableClusterInfo, clusterInfoCols, dataForClusterInfo)
+	inspection.ClusterVersions = func(ctx sessionctx.Context) ([]inspection.VersionInfo, error) {
+		rows, err := dataForClusterInfo(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		out := make([]inspection.VersionInfo, 0, len(rows))
+		for _, row := range rows {
+			out = append(out, inspection.VersionInfo{ServerType: row[0], Address: row[1], Version: row[2]})
+		}
+		return out, nil
+	}
+}
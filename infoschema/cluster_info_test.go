@@ -0,0 +1,73 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/infoschema/inspection"
+)
+
+// TestFetchClusterVersionsSurfacesErrors exercises fetchClusterVersions end
+// to end: a working "/status" endpoint should yield its version, and a
+// broken one should surface as one VERSION="error: ..." row rather than
+// aborting the whole query.
+func TestFetchClusterVersionsSurfacesErrors(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "v5.0.0"}`))
+	}))
+	defer ok.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	instances := []clusterConfigInstance{
+		{ServerType: "tidb", Address: "tidb-1", StatusAddr: strings.TrimPrefix(ok.URL, "http://")},
+		{ServerType: "tikv", Address: "tikv-1", StatusAddr: strings.TrimPrefix(bad.URL, "http://")},
+	}
+
+	rows := fetchClusterVersions(context.Background(), instances)
+
+	byAddr := map[string][]string{}
+	for _, row := range rows {
+		byAddr[row[1]] = row
+	}
+	if row, ok := byAddr["tidb-1"]; !ok || row[2] != "v5.0.0" {
+		t.Errorf("tidb-1 row = %+v, want version v5.0.0", row)
+	}
+	row, ok := byAddr["tikv-1"]
+	if !ok {
+		t.Fatalf("tikv-1 missing from rows")
+	}
+	if !strings.HasPrefix(row[2], "error: ") {
+		t.Errorf("tikv-1 VERSION = %q, want it to surface the fetch error", row[2])
+	}
+}
+
+// TestClusterInfoRegistersInspectionClusterVersions guards against
+// CLUSTER_INFO being registered but inspection.ClusterVersions left unset,
+// which would silently disable the version-skew rule.
+func TestClusterInfoRegistersInspectionClusterVersions(t *testing.T) {
+	if _, ok := virtualTables[TableClusterInfo]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableClusterInfo)
+	}
+	if inspection.ClusterVersions == nil {
+		t.Fatalf("inspection.ClusterVersions was not set")
+	}
+}
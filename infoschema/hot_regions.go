@@ -0,0 +1,234 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/pdapi"
+)
+
+// TableHotRegions and TableRegionPeers are the names
+// INFORMATION_SCHEMA.TIDB_HOT_REGIONS and INFORMATION_SCHEMA.TIDB_REGION_PEERS
+// are registered under.
+const (
+	TableHotRegions  = "TIDB_HOT_REGIONS"
+	TableRegionPeers = "TIDB_REGION_PEERS"
+)
+
+var hotRegionsCols = []columnInfo{
+	{name: "REGION_ID", typeName: "bigint"},
+	{name: "STORE_ID", typeName: "bigint"},
+	{name: "PEER_ID", typeName: "bigint"},
+	{name: "IS_LEADER", typeName: "tinyint"},
+	{name: "TYPE", typeName: "varchar(16)"},
+	{name: "TABLE_ID", typeName: "bigint"},
+	{name: "DB_NAME", typeName: "varchar(64)"},
+	{name: "TABLE_NAME", typeName: "varchar(64)"},
+	{name: "INDEX_ID", typeName: "bigint"},
+	{name: "INDEX_NAME", typeName: "varchar(64)"},
+	{name: "FLOW_BYTES", typeName: "bigint"},
+	{name: "HOT_DEGREE", typeName: "bigint"},
+	{name: "UPDATED_AT", typeName: "datetime"},
+}
+
+var regionPeersCols = []columnInfo{
+	{name: "REGION_ID", typeName: "bigint"},
+	{name: "PEER_ID", typeName: "bigint"},
+	{name: "STORE_ID", typeName: "bigint"},
+	{name: "IS_LEARNER", typeName: "tinyint"},
+	{name: "IS_LEADER", typeName: "tinyint"},
+	{name: "STATUS", typeName: "varchar(16)"},
+	{name: "DOWN_SECONDS", typeName: "bigint"},
+}
+
+func init() {
+	registerVirtualTable(TableHotRegions, hotRegionsCols, fetchPDHotRegions)
+	registerVirtualTable(TableRegionPeers, regionPeersCols, fetchPDRegionPeers)
+}
+
+// pdHotRegionEntry is the subset of PD's /hotspot/regions/{read,write}
+// response this file cares about; PD nests these under a "regions" or
+// per-store key depending on version, but always carries at least these
+// fields per hot region.
+type pdHotRegionEntry struct {
+	RegionID       int64  `json:"region_id"`
+	StoreID        int64  `json:"store_id"`
+	PeerID         int64  `json:"peer_id"`
+	FlowBytes      int64  `json:"flow_bytes"`
+	HotDegree      int64  `json:"hot_degree"`
+	LastUpdateTime int64  `json:"last_update_time"` // unix seconds
+	StartKey       string `json:"start_key"`
+}
+
+// pdRegionPeer mirrors one element of PD's /regions/store/{id} response.
+type pdRegionPeer struct {
+	RegionID    int64  `json:"id"`
+	PeerID      int64  `json:"peer_id"`
+	StoreID     int64  `json:"store_id"`
+	IsLearner   bool   `json:"is_learner"`
+	IsLeader    bool   `json:"is_leader"`
+	Status      string `json:"status"`
+	DownSeconds int64  `json:"down_seconds"`
+}
+
+// HotRegionRow is one row of INFORMATION_SCHEMA.TIDB_HOT_REGIONS.
+type HotRegionRow struct {
+	RegionID  int64
+	StoreID   int64
+	PeerID    int64
+	IsLeader  bool
+	Type      string // "read" or "write"
+	TableID   int64
+	DBName    string
+	TableName string
+	IndexID   int64
+	IndexName string
+	FlowBytes int64
+	HotDegree int64
+	UpdatedAt time.Time
+}
+
+// RegionPeerRow is one row of INFORMATION_SCHEMA.TIDB_REGION_PEERS.
+type RegionPeerRow struct {
+	RegionID    int64
+	PeerID      int64
+	StoreID     int64
+	IsLearner   bool
+	IsLeader    bool
+	Status      string
+	DownSeconds int64
+}
+
+// fetchPDHotRegions GETs PD's hotspot endpoint for kind ("read" or "write")
+// and resolves each entry's start key back to the owning table/index via
+// tablecodec, so a query can pinpoint hotspot tables/indices directly
+// instead of cross-referencing region IDs by hand. tableNamer resolves a
+// tableID to the schema/table names information_schema.TABLES already
+// knows, plus that table's indexID->name map. Rows whose key doesn't decode
+// to a table leave TABLE_ID/INDEX_ID zero and every name blank; rows whose
+// table isn't found (e.g. it's since been dropped), or whose index isn't in
+// the map, still carry TABLE_ID/INDEX_ID but leave DB_NAME, TABLE_NAME
+// and/or INDEX_NAME blank.
+//
+// PD reports hot regions under two, not necessarily identical, groupings:
+// as_leader (the region's leader peer, which serves most reads/writes) and
+// as_peer (every peer, including followers serving stale/follower reads).
+// Both are real rows a caller inspecting hot indices needs, distinguished
+// by IS_LEADER.
+func fetchPDHotRegions(ctx context.Context, pdAddr, kind string, tableNamer func(tableID int64) (db, table string, indexNames map[int64]string, ok bool)) ([]HotRegionRow, error) {
+	path := pdapi.HotRead
+	if kind == "write" {
+		path = pdapi.HotWrite
+	}
+	var resp struct {
+		AsPeer   map[string][]pdHotRegionEntry `json:"as_peer"`
+		AsLeader map[string][]pdHotRegionEntry `json:"as_leader"`
+	}
+	if err := getPDJSON(ctx, pdAddr, path, &resp); err != nil {
+		return nil, err
+	}
+
+	rows := make([]HotRegionRow, 0, len(resp.AsLeader)+len(resp.AsPeer))
+	appendRows := func(grouped map[string][]pdHotRegionEntry, isLeader bool) {
+		for _, entries := range grouped {
+			for _, e := range entries {
+				row := HotRegionRow{
+					RegionID:  e.RegionID,
+					StoreID:   e.StoreID,
+					PeerID:    e.PeerID,
+					IsLeader:  isLeader,
+					Type:      kind,
+					FlowBytes: e.FlowBytes,
+					HotDegree: e.HotDegree,
+					UpdatedAt: time.Unix(e.LastUpdateTime, 0),
+				}
+				if tableID, indexID, ok := decodeRegionStartKey(e.StartKey); ok {
+					row.TableID, row.IndexID = tableID, indexID
+					if db, table, indexNames, found := tableNamer(tableID); found {
+						row.DBName, row.TableName = db, table
+						if indexID > 0 {
+							row.IndexName = indexNames[indexID]
+						}
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	appendRows(resp.AsLeader, true)
+	appendRows(resp.AsPeer, false)
+	return rows, nil
+}
+
+// fetchPDRegionPeers GETs PD's per-store region list and converts it to
+// RegionPeerRow, one row per peer.
+func fetchPDRegionPeers(ctx context.Context, pdAddr string, storeID int64) ([]RegionPeerRow, error) {
+	var resp struct {
+		Regions []pdRegionPeer `json:"regions"`
+	}
+	if err := getPDJSON(ctx, pdAddr, fmt.Sprintf("%s%d", pdapi.RegionsByStore, storeID), &resp); err != nil {
+		return nil, err
+	}
+	rows := make([]RegionPeerRow, 0, len(resp.Regions))
+	for _, p := range resp.Regions {
+		rows = append(rows, RegionPeerRow{
+			RegionID:    p.RegionID,
+			PeerID:      p.PeerID,
+			StoreID:     p.StoreID,
+			IsLearner:   p.IsLearner,
+			IsLeader:    p.IsLeader,
+			Status:      p.Status,
+			DownSeconds: p.DownSeconds,
+		})
+	}
+	return rows, nil
+}
+
+// decodeRegionStartKey decodes a region's raw start key (hex-encoded, as PD
+// returns it) into the table/index ID it falls within, if any. Region keys
+// that fall outside the table key-space (e.g. meta regions) return ok=false.
+func decodeRegionStartKey(hexKey string) (tableID, indexID int64, ok bool) {
+	key, err := tablecodec.DecodeRegionKeyHex(hexKey)
+	if err != nil {
+		return 0, 0, false
+	}
+	tableID, indexID, _, err = tablecodec.DecodeKeyHead(key)
+	if err != nil {
+		return 0, 0, false
+	}
+	return tableID, indexID, true
+}
+
+// getPDJSON GETs path off pdAddr and decodes the JSON body into out.
+func getPDJSON(ctx context.Context, pdAddr, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+pdAddr+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s%s: unexpected status %s", pdAddr, path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/tablecodec"
+)
+
+// TestFetchPDHotRegionsResolvesIndexNameAndKeepsPeerRows guards against two
+// regressions: index names silently falling back to a fabricated "idx_%d"
+// instead of the real schema name, and as_peer rows being dropped entirely
+// in favor of as_leader.
+func TestFetchPDHotRegionsResolvesIndexNameAndKeepsPeerRows(t *testing.T) {
+	const tableID, indexID = 42, 7
+	startKey := hex.EncodeToString(tablecodec.EncodeTableIndexPrefix(tableID, indexID))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"as_leader": {"store1": [{"region_id":1,"store_id":1,"peer_id":11,"flow_bytes":100,"hot_degree":1,"last_update_time":1000,"start_key":"%s"}]},
+			"as_peer": {"store2": [{"region_id":1,"store_id":2,"peer_id":12,"flow_bytes":50,"hot_degree":1,"last_update_time":1000,"start_key":"%s"}]}
+		}`, startKey, startKey)
+	}))
+	defer srv.Close()
+
+	tableNamer := func(id int64) (db, table string, indexNames map[int64]string, ok bool) {
+		if id != tableID {
+			return "", "", nil, false
+		}
+		return "test", "t", map[int64]string{indexID: "idx_on_a"}, true
+	}
+
+	rows, err := fetchPDHotRegions(context.Background(), strings.TrimPrefix(srv.URL, "http://"), "read", tableNamer)
+	if err != nil {
+		t.Fatalf("fetchPDHotRegions: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (one as_leader, one as_peer)", len(rows))
+	}
+
+	var sawLeader, sawPeer bool
+	for _, row := range rows {
+		if row.TableID != tableID || row.IndexID != indexID {
+			t.Errorf("row %+v: table/index id mismatch", row)
+		}
+		if row.IndexName != "idx_on_a" {
+			t.Errorf("row %+v: IndexName = %q, want the real schema name, not a fabricated one", row, row.IndexName)
+		}
+		if row.IsLeader {
+			sawLeader = true
+		} else {
+			sawPeer = true
+		}
+	}
+	if !sawLeader || !sawPeer {
+		t.Errorf("expected both a leader row and a peer row, got sawLeader=%v sawPeer=%v", sawLeader, sawPeer)
+	}
+}
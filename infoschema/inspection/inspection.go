@@ -0,0 +1,125 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inspection holds the rule registry behind
+// INFORMATION_SCHEMA.INSPECTION_RESULT. A rule is a plain Go function that
+// looks at the same cluster-wide data CLUSTER_CONFIG/CLUSTER_LOAD/CLUSTER_INFO
+// expose and reports anything worth an operator's attention; registering one
+// here is what makes `SELECT * FROM INSPECTION_RESULT` include it.
+package inspection
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// Result is one row of INFORMATION_SCHEMA.INSPECTION_RESULT.
+type Result struct {
+	Rule      string
+	Item      string
+	Type      string
+	Instance  string
+	Value     string
+	Reference string
+	Severity  string
+	Details   string
+}
+
+// Severity values a Rule may report. There's no enforced enum beyond these
+// two; a rule is free to use its own string, but should prefer one of these
+// so `WHERE severity = 'critical'` means the same thing across rules.
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Rule inspects the cluster and reports anything that deviates from what
+// it considers healthy. It's handed the session so it can read the
+// variables/privileges of the query that triggered it, the same way a
+// CLUSTER_* table reader is.
+type Rule func(ctx sessionctx.Context) []Result
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Rule{}
+)
+
+// RegisterRule adds fn under name to the set INSPECTION_RESULT runs. It's
+// meant to be called from an init() in the file that defines the rule, the
+// same way the builtin rules in rules.go register themselves; a name
+// registered twice panics, since that can only be a programming mistake.
+func RegisterRule(name string, fn Rule) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic("inspection: rule " + name + " already registered")
+	}
+	registry[name] = fn
+}
+
+// Names returns the names of every registered rule, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes the named rules against ctx and returns their combined
+// results, sorted by (rule, item) for a stable row order. A nil or empty
+// wanted runs every registered rule; a name in wanted that isn't
+// registered is silently ignored, so `WHERE rule IN (...)` can list rules
+// this build doesn't know about without erroring.
+func Run(ctx sessionctx.Context, wanted []string) []Result {
+	mu.RLock()
+	rules := make(map[string]Rule, len(registry))
+	for name, fn := range registry {
+		rules[name] = fn
+	}
+	mu.RUnlock()
+
+	names := wanted
+	if len(names) == 0 {
+		names = make([]string, 0, len(rules))
+		for name := range rules {
+			names = append(names, name)
+		}
+	}
+
+	var results []Result
+	for _, name := range names {
+		fn, ok := rules[name]
+		if !ok {
+			continue
+		}
+		for _, r := range fn(ctx) {
+			if r.Rule == "" {
+				r.Rule = name
+			}
+			results = append(results, r)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rule != results[j].Rule {
+			return results[i].Rule < results[j].Rule
+		}
+		return results[i].Item < results[j].Item
+	})
+	return results
+}
@@ -0,0 +1,273 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspection
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// The CPU/memory thresholds below which a CLUSTER_LOAD sample is left
+// alone; crossing one is what turns a sample into a "resource-threshold"
+// result.
+const (
+	cpuWarnPercent = 80.0
+	memWarnPercent = 80.0
+	cpuCritPercent = 95.0
+	memCritPercent = 95.0
+)
+
+// ConfigInstance is one component's flattened config, the same shape
+// CLUSTER_CONFIG itself projects. It's the input the config-consistency
+// rule compares across instances.
+type ConfigInstance struct {
+	ServerType string
+	Address    string
+	Config     map[string]string
+}
+
+// VersionInfo is one component's reported build version, as CLUSTER_INFO
+// surfaces it.
+type VersionInfo struct {
+	ServerType string
+	Address    string
+	Version    string
+}
+
+// LoadSample is one component's point-in-time CPU/memory usage, as
+// CLUSTER_LOAD surfaces it.
+type LoadSample struct {
+	ServerType string
+	Address    string
+	CPUPercent float64
+	MemPercent float64
+}
+
+// TiFlashReplicaStatus is one table's TiFlash replica state, as
+// INFORMATION_SCHEMA.TABLES' TIFLASH_REPLICA columns surface it.
+type TiFlashReplicaStatus struct {
+	DBName          string
+	TableName       string
+	Available       bool
+	ProgressPercent float64
+}
+
+// The hooks below are this package's only dependency on live cluster data.
+// ClusterConfigs and ClusterVersions are set from package infoschema's
+// init() (cluster_config.go and cluster_info.go). Nothing in this tree sets
+// ClusterLoad or TiFlashReplicas, so resource-threshold and tiflash-replica
+// report nothing until a caller assigns them. A rule whose hook is nil just
+// reports nothing, rather than erroring the whole INSPECTION_RESULT query
+// over one missing rule's data source.
+var (
+	ClusterConfigs  func(ctx sessionctx.Context) ([]ConfigInstance, error)
+	ClusterVersions func(ctx sessionctx.Context) ([]VersionInfo, error)
+	ClusterLoad     func(ctx sessionctx.Context) ([]LoadSample, error)
+	TiFlashReplicas func(ctx sessionctx.Context) ([]TiFlashReplicaStatus, error)
+)
+
+func init() {
+	RegisterRule("config-consistency", configConsistencyRule)
+	RegisterRule("version-skew", versionSkewRule)
+	RegisterRule("resource-threshold", resourceThresholdRule)
+	RegisterRule("tiflash-replica", tiflashReplicaRule)
+}
+
+// configConsistencyRule flags a config key whose value differs across
+// instances of the same component type, e.g. one TiKV running with a
+// different block-cache-size than its peers after a partial rolling
+// change.
+func configConsistencyRule(ctx sessionctx.Context) []Result {
+	if ClusterConfigs == nil {
+		return nil
+	}
+	instances, err := ClusterConfigs(ctx)
+	if err != nil {
+		return []Result{{Item: "fetch", Value: err.Error(), Severity: SeverityWarning, Details: "could not fetch cluster config"}}
+	}
+
+	// valuesByType[serverType][key] -> value seen -> one representative address.
+	valuesByType := map[string]map[string]map[string]string{}
+	for _, inst := range instances {
+		byKey, ok := valuesByType[inst.ServerType]
+		if !ok {
+			byKey = map[string]map[string]string{}
+			valuesByType[inst.ServerType] = byKey
+		}
+		for key, value := range inst.Config {
+			seen, ok := byKey[key]
+			if !ok {
+				seen = map[string]string{}
+				byKey[key] = seen
+			}
+			seen[value] = inst.Address
+		}
+	}
+
+	var results []Result
+	for serverType, byKey := range valuesByType {
+		for key, seen := range byKey {
+			if len(seen) <= 1 {
+				continue
+			}
+			for value, addr := range seen {
+				results = append(results, Result{
+					Item:      key,
+					Type:      serverType,
+					Instance:  addr,
+					Value:     value,
+					Reference: "same value on every " + serverType + " instance",
+					Severity:  SeverityWarning,
+					Details:   fmt.Sprintf("%s.%s differs across %d distinct values cluster-wide", serverType, key, len(seen)),
+				})
+			}
+		}
+	}
+	return results
+}
+
+// versionSkewRule flags a component instance whose reported version
+// doesn't match the majority of its own type, the common symptom of a
+// rolling upgrade that stalled partway through.
+func versionSkewRule(ctx sessionctx.Context) []Result {
+	if ClusterVersions == nil {
+		return nil
+	}
+	versions, err := ClusterVersions(ctx)
+	if err != nil {
+		return []Result{{Item: "fetch", Value: err.Error(), Severity: SeverityWarning, Details: "could not fetch cluster version info"}}
+	}
+
+	counts := map[string]map[string]int{}
+	for _, v := range versions {
+		byVersion, ok := counts[v.ServerType]
+		if !ok {
+			byVersion = map[string]int{}
+			counts[v.ServerType] = byVersion
+		}
+		byVersion[v.Version]++
+	}
+
+	majority := map[string]string{}
+	for serverType, byVersion := range counts {
+		var best string
+		var bestCount int
+		for version, count := range byVersion {
+			if count > bestCount {
+				best, bestCount = version, count
+			}
+		}
+		majority[serverType] = best
+	}
+
+	var results []Result
+	for _, v := range versions {
+		if v.Version == majority[v.ServerType] {
+			continue
+		}
+		results = append(results, Result{
+			Item:      "version",
+			Type:      v.ServerType,
+			Instance:  v.Address,
+			Value:     v.Version,
+			Reference: majority[v.ServerType],
+			Severity:  SeverityWarning,
+			Details:   fmt.Sprintf("%s at %s runs %s, the rest of the %ss run %s", v.ServerType, v.Address, v.Version, v.ServerType, majority[v.ServerType]),
+		})
+	}
+	return results
+}
+
+// resourceThresholdRule flags a CLUSTER_LOAD sample whose CPU or memory
+// usage crosses the warn/critical thresholds above.
+func resourceThresholdRule(ctx sessionctx.Context) []Result {
+	if ClusterLoad == nil {
+		return nil
+	}
+	samples, err := ClusterLoad(ctx)
+	if err != nil {
+		return []Result{{Item: "fetch", Value: err.Error(), Severity: SeverityWarning, Details: "could not fetch cluster load"}}
+	}
+
+	var results []Result
+	for _, s := range samples {
+		if sev, ok := thresholdSeverity(s.CPUPercent, cpuWarnPercent, cpuCritPercent); ok {
+			results = append(results, Result{
+				Item:      "cpu",
+				Type:      s.ServerType,
+				Instance:  s.Address,
+				Value:     fmt.Sprintf("%.1f%%", s.CPUPercent),
+				Reference: fmt.Sprintf("< %.0f%%", cpuWarnPercent),
+				Severity:  sev,
+				Details:   fmt.Sprintf("%s at %s is at %.1f%% CPU", s.ServerType, s.Address, s.CPUPercent),
+			})
+		}
+		if sev, ok := thresholdSeverity(s.MemPercent, memWarnPercent, memCritPercent); ok {
+			results = append(results, Result{
+				Item:      "memory",
+				Type:      s.ServerType,
+				Instance:  s.Address,
+				Value:     fmt.Sprintf("%.1f%%", s.MemPercent),
+				Reference: fmt.Sprintf("< %.0f%%", memWarnPercent),
+				Severity:  sev,
+				Details:   fmt.Sprintf("%s at %s is at %.1f%% memory", s.ServerType, s.Address, s.MemPercent),
+			})
+		}
+	}
+	return results
+}
+
+// thresholdSeverity reports whether value crosses warn or crit, and if so
+// which severity that crossing is.
+func thresholdSeverity(value, warn, crit float64) (severity string, flagged bool) {
+	switch {
+	case value >= crit:
+		return SeverityCritical, true
+	case value >= warn:
+		return SeverityWarning, true
+	default:
+		return "", false
+	}
+}
+
+// tiflashReplicaRule flags a table whose TiFlash replica was requested
+// but hasn't become available, so a query relying on it for MPP/analytics
+// silently falls back to TiKV instead of erroring.
+func tiflashReplicaRule(ctx sessionctx.Context) []Result {
+	if TiFlashReplicas == nil {
+		return nil
+	}
+	statuses, err := TiFlashReplicas(ctx)
+	if err != nil {
+		return []Result{{Item: "fetch", Value: err.Error(), Severity: SeverityWarning, Details: "could not fetch TiFlash replica status"}}
+	}
+
+	var results []Result
+	for _, t := range statuses {
+		if t.Available {
+			continue
+		}
+		results = append(results, Result{
+			Item:      "tiflash-replica",
+			Type:      "tiflash",
+			Instance:  fmt.Sprintf("%s.%s", t.DBName, t.TableName),
+			Value:     fmt.Sprintf("%.1f%%", t.ProgressPercent),
+			Reference: "100%",
+			Severity:  SeverityWarning,
+			Details:   fmt.Sprintf("%s.%s's TiFlash replica is %.1f%% caught up and not yet available", t.DBName, t.TableName, t.ProgressPercent),
+		})
+	}
+	return results
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"github.com/pingcap/tidb/infoschema/inspection"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// TableInspectionResult is the name INFORMATION_SCHEMA.INSPECTION_RESULT is
+// registered under.
+const TableInspectionResult = "INSPECTION_RESULT"
+
+// inspectionResultCols mirrors inspection.Result field-for-field.
+var inspectionResultCols = []columnInfo{
+	{name: "RULE", typeName: "varchar(64)"},
+	{name: "ITEM", typeName: "varchar(64)"},
+	{name: "TYPE", typeName: "varchar(64)"},
+	{name: "INSTANCE", typeName: "varchar(64)"},
+	{name: "VALUE", typeName: "varchar(256)"},
+	{name: "REFERENCE", typeName: "varchar(256)"},
+	{name: "SEVERITY", typeName: "varchar(16)"},
+	{name: "DETAILS", typeName: "varchar(1024)"},
+}
+
+// dataForInspectionResult is INSPECTION_RESULT's row source: it runs the
+// rules named in wanted (or every registered rule, if wanted is empty)
+// against ctx and flattens the results inspection.Run already sorted into
+// rows matching inspectionResultCols.
+func dataForInspectionResult(ctx sessionctx.Context, wanted []string) ([][]string, error) {
+	results := inspection.Run(ctx, wanted)
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{r.Rule, r.Item, r.Type, r.Instance, r.Value, r.Reference, r.Severity, r.Details})
+	}
+	return rows, nil
+}
+
+func init() {
+	registerVirtualTable(TableInspectionResult, inspectionResultCols, dataForInspectionResult)
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/infoschema/inspection"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+func init() {
+	inspection.RegisterRule("test-inspection-result-rule", func(ctx sessionctx.Context) []inspection.Result {
+		return []inspection.Result{{
+			Item:      "probe",
+			Type:      "tidb",
+			Instance:  "127.0.0.1:4000",
+			Value:     "1",
+			Reference: "0",
+			Severity:  inspection.SeverityWarning,
+			Details:   "test rule always fires",
+		}}
+	})
+}
+
+// TestDataForInspectionResultFlattensRows guards against INSPECTION_RESULT
+// being defined but never registered: without registerVirtualTable, this
+// row source is unreachable from SQL no matter how correct it is.
+func TestDataForInspectionResultFlattensRows(t *testing.T) {
+	if _, ok := virtualTables[TableInspectionResult]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableInspectionResult)
+	}
+
+	rows, err := dataForInspectionResult(nil, []string{"test-inspection-result-rule"})
+	if err != nil {
+		t.Fatalf("dataForInspectionResult: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	want := []string{"test-inspection-result-rule", "probe", "tidb", "127.0.0.1:4000", "1", "0", inspection.SeverityWarning, "test rule always fires"}
+	for i, v := range want {
+		if row[i] != v {
+			t.Errorf("row[%d] = %q, want %q", i, row[i], v)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"strconv"
+
+	"github.com/pingcap/tidb/util"
+)
+
+// TableKillHistory is the name INFORMATION_SCHEMA.KILL_HISTORY is
+// registered under.
+const TableKillHistory = "KILL_HISTORY"
+
+var killHistoryCols = []columnInfo{
+	{name: "TIME", typeName: "datetime"},
+	{name: "CONNECTION_ID", typeName: "bigint unsigned"},
+	{name: "USER", typeName: "varchar(64)"},
+	{name: "HOST", typeName: "varchar(255)"},
+	{name: "REASON", typeName: "varchar(32)"},
+	{name: "KILLED_BY_USER", typeName: "varchar(64)"},
+	{name: "STMT_DIGEST", typeName: "varchar(64)"},
+	{name: "DETAIL", typeName: "varchar(256)"},
+}
+
+// dataForKillHistory is KILL_HISTORY's row source: it snapshots
+// util.KillHistory(), the buffer every kill (admin-issued or
+// server-triggered) is expected to record itself into via
+// util.RecordKill.
+func dataForKillHistory() ([][]string, error) {
+	entries := util.KillHistory()
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Time.Format("2006-01-02 15:04:05"),
+			strconv.FormatUint(e.ConnectionID, 10),
+			e.User,
+			e.Host,
+			e.Reason.String(),
+			e.KilledByUser,
+			e.StmtDigest,
+			e.Detail,
+		})
+	}
+	return rows, nil
+}
+
+func init() {
+	registerVirtualTable(TableKillHistory, killHistoryCols, dataForKillHistory)
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/util"
+)
+
+// TestDataForKillHistoryReflectsRecordedKills guards against KILL_HISTORY
+// being registered but disconnected from util.RecordKill.
+func TestDataForKillHistoryReflectsRecordedKills(t *testing.T) {
+	if _, ok := virtualTables[TableKillHistory]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableKillHistory)
+	}
+
+	before, err := dataForKillHistory()
+	if err != nil {
+		t.Fatalf("dataForKillHistory: %v", err)
+	}
+	util.RecordKill(99, "root", "127.0.0.1", util.KillReasonMaxExecTimeExceeded, "admin", "digest1", "max-exec-time")
+
+	after, err := dataForKillHistory()
+	if err != nil {
+		t.Fatalf("dataForKillHistory: %v", err)
+	}
+	if len(after) != len(before)+1 {
+		t.Fatalf("got %d rows, want %d", len(after), len(before)+1)
+	}
+	last := after[len(after)-1]
+	if last[1] != "99" || last[2] != "root" || last[3] != "127.0.0.1" || last[4] != "max execution time exceeded" ||
+		last[5] != "admin" || last[6] != "digest1" || last[7] != "max-exec-time" {
+		t.Errorf("row = %+v, unexpected", last)
+	}
+}
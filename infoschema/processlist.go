@@ -0,0 +1,91 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tidb/util"
+)
+
+// TableProcessList is the name INFORMATION_SCHEMA.PROCESSLIST is
+// registered under.
+const TableProcessList = "PROCESSLIST"
+
+var processListCols = []columnInfo{
+	{name: "ID", typeName: "bigint unsigned"},
+	{name: "USER", typeName: "varchar(64)"},
+	{name: "HOST", typeName: "varchar(255)"},
+	{name: "DB", typeName: "varchar(64)"},
+	{name: "COMMAND", typeName: "varchar(16)"},
+	{name: "STATE", typeName: "varchar(7)"},
+	{name: "INFO", typeName: "longtext"},
+	{name: "TXN_STATE", typeName: "varchar(16)"},
+	{name: "LOCK_WAIT_START_TS", typeName: "bigint unsigned"},
+	{name: "WAITING_FOR_TXN_ID", typeName: "bigint unsigned"},
+	{name: "MEM_USAGE_BYTES", typeName: "bigint"},
+}
+
+// processListSessionManager is how dataForProcessList learns the set of
+// live sessions to report. It's set by SetProcessListSessionManager during
+// server startup; left nil, PROCESSLIST simply returns no rows instead of
+// erroring.
+var processListSessionManager util.SessionManager
+
+// SetProcessListSessionManager wires mgr as PROCESSLIST's source of live
+// sessions. Called once, from server startup, after the real
+// SessionManager implementation (in the session package, which isn't part
+// of this tree) exists.
+func SetProcessListSessionManager(mgr util.SessionManager) {
+	processListSessionManager = mgr
+}
+
+// dataForProcessList is PROCESSLIST's row source: it snapshots
+// processListSessionManager.ShowProcessList(), sorted by connection ID so
+// repeated SELECTs return a stable order.
+func dataForProcessList() ([][]string, error) {
+	if processListSessionManager == nil {
+		return nil, nil
+	}
+	processes := processListSessionManager.ShowProcessList()
+	ids := make([]uint64, 0, len(processes))
+	for id := range processes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	rows := make([][]string, 0, len(processes))
+	for _, id := range ids {
+		p := processes[id]
+		rows = append(rows, []string{
+			strconv.FormatUint(p.ID, 10),
+			p.User,
+			p.Host,
+			p.DB,
+			strconv.Itoa(int(p.Command)),
+			strconv.Itoa(int(p.State)),
+			p.Info,
+			p.TxnState,
+			strconv.FormatUint(p.LockWaitStartTS, 10),
+			strconv.FormatUint(p.WaitingForTxnID, 10),
+			strconv.FormatInt(p.MemUsageBytes, 10),
+		})
+	}
+	return rows, nil
+}
+
+func init() {
+	registerVirtualTable(TableProcessList, processListCols, dataForProcessList)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/util"
+)
+
+// fakeSessionManager is a minimal util.SessionManager standing in for the
+// real session package implementation, which isn't part of this tree.
+type fakeSessionManager struct {
+	processes map[uint64]*util.ProcessInfo
+}
+
+func (m *fakeSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	return m.processes
+}
+
+func (m *fakeSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	p, ok := m.processes[id]
+	return p, ok
+}
+
+func (m *fakeSessionManager) Kill(connectionID uint64, query bool) {}
+
+// TestDataForProcessListReflectsSessionManager guards against PROCESSLIST
+// being registered but disconnected from SetProcessListSessionManager, and
+// against the TXN_STATE/LOCK_WAIT_START_TS/WAITING_FOR_TXN_ID/
+// MEM_USAGE_BYTES columns losing their source field.
+func TestDataForProcessListReflectsSessionManager(t *testing.T) {
+	if _, ok := virtualTables[TableProcessList]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableProcessList)
+	}
+
+	if rows, err := dataForProcessList(); err != nil || rows != nil {
+		t.Fatalf("dataForProcessList() with no SessionManager = %v, %v, want nil, nil", rows, err)
+	}
+
+	SetProcessListSessionManager(&fakeSessionManager{
+		processes: map[uint64]*util.ProcessInfo{
+			7: {
+				ID: 7, User: "root", Host: "127.0.0.1", DB: "test",
+				TxnState: util.TxnStateActive, LockWaitStartTS: 100,
+				WaitingForTxnID: 50, MemUsageBytes: 4096,
+			},
+		},
+	})
+	defer SetProcessListSessionManager(nil)
+
+	rows, err := dataForProcessList()
+	if err != nil {
+		t.Fatalf("dataForProcessList: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	if row[0] != "7" || row[1] != "root" || row[2] != "127.0.0.1" || row[3] != "test" ||
+		row[7] != util.TxnStateActive || row[8] != "100" || row[9] != "50" || row[10] != "4096" {
+		t.Errorf("row = %+v, unexpected", row)
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableSlowQueryJSON is the name INFORMATION_SCHEMA.SLOW_QUERY_JSON is
+// registered under.
+const TableSlowQueryJSON = "SLOW_QUERY_JSON"
+
+// TableClusterSlowQueryJSON is the name
+// INFORMATION_SCHEMA.CLUSTER_SLOW_QUERY_JSON is registered under.
+const TableClusterSlowQueryJSON = "CLUSTER_SLOW_QUERY_JSON"
+
+// slowQueryJSONCols is shared by SLOW_QUERY_JSON and (with a leading
+// INSTANCE column prepended) CLUSTER_SLOW_QUERY_JSON.
+//
+// BACKOFF_TYPES and the COP_* columns expose the JSON log's nested
+// "backoff_types" array and "cop" object as already-flattened typed
+// columns, rather than as a single JSON column plus MySQL-style
+// GENERATED ALWAYS AS (json_extract(...)) columns: a virtual table here
+// is only a columnInfo{name, typeName} list plus a row-producing
+// function (see virtualtable.go), with no generated-column/JSON-type
+// machinery for a DDL-level json_extract to hang off of.
+var slowQueryJSONCols = []columnInfo{
+	{name: "TIME", typeName: "datetime"},
+	{name: "TXN_START_TS", typeName: "bigint unsigned"},
+	{name: "USER", typeName: "varchar(64)"},
+	{name: "HOST", typeName: "varchar(64)"},
+	{name: "CONN_ID", typeName: "bigint unsigned"},
+	{name: "QUERY_TIME", typeName: "double"},
+	{name: "PARSE_TIME", typeName: "double"},
+	{name: "COMPILE_TIME", typeName: "double"},
+	{name: "PROCESS_TIME", typeName: "double"},
+	{name: "WAIT_TIME", typeName: "double"},
+	{name: "BACKOFF_TIME", typeName: "double"},
+	{name: "BACKOFF_TYPES", typeName: "varchar(256)"},
+	{name: "COP_PROCESS_AVG_MS", typeName: "double"},
+	{name: "COP_PROCESS_P90_MS", typeName: "double"},
+	{name: "COP_PROCESS_MAX_MS", typeName: "double"},
+	{name: "COP_WAIT_AVG_MS", typeName: "double"},
+	{name: "COP_WAIT_P90_MS", typeName: "double"},
+	{name: "COP_WAIT_MAX_MS", typeName: "double"},
+	{name: "DB", typeName: "varchar(64)"},
+	{name: "IS_INTERNAL", typeName: "tinyint"},
+	{name: "DIGEST", typeName: "varchar(64)"},
+	{name: "STATS", typeName: "varchar(1024)"},
+	{name: "MEM_MAX", typeName: "bigint"},
+	{name: "SUCC", typeName: "tinyint"},
+	{name: "PLAN", typeName: "longtext"},
+	{name: "PREV_STMT", typeName: "longtext"},
+	{name: "QUERY", typeName: "longtext"},
+}
+
+var clusterSlowQueryJSONCols = append([]columnInfo{{name: "INSTANCE", typeName: "varchar(64)"}}, slowQueryJSONCols...)
+
+func init() {
+	registerVirtualTable(TableSlowQueryJSON, slowQueryJSONCols, dataForSlowQueryJSON)
+	registerVirtualTable(TableClusterSlowQueryJSON, clusterSlowQueryJSONCols, dataForClusterSlowQueryJSON)
+}
+
+// SlowQueryJSONRow is one row of INFORMATION_SCHEMA.SLOW_QUERY_JSON. It
+// carries the same fields as the `# Key: value` slow log format the plain
+// SLOW_QUERY table reads, for deployments that configure
+// tidb-slow-log-format=json so the log can be ingested by JSON-only log
+// pipelines without a bespoke parser on the other end.
+type SlowQueryJSONRow struct {
+	Time            time.Time
+	TxnStartTS      uint64
+	User            string
+	Host            string
+	ConnID          uint64
+	QueryTime       float64
+	ParseTime       float64
+	CompileTime     float64
+	ProcessTime     float64
+	WaitTime        float64
+	BackoffTime     float64
+	BackoffTypes    []string
+	CopProcessAvgMs float64
+	CopProcessP90Ms float64
+	CopProcessMaxMs float64
+	CopWaitAvgMs    float64
+	CopWaitP90Ms    float64
+	CopWaitMaxMs    float64
+	DB              string
+	IsInternal      bool
+	Digest          string
+	Stats           string
+	MemMax          int64
+	Succ            bool
+	Plan            string
+	PrevStmt        string
+	Query           string
+}
+
+// slowQueryJSONCopStats is slowQueryJSONLine's nested "cop" object: the
+// coprocessor-request latency distribution TiDB already tracks per query,
+// serialized as a nested object instead of flat Cop_* keys the way the
+// text format's `# Cop_process_avg: ...` lines spell them.
+type slowQueryJSONCopStats struct {
+	ProcessAvgMs float64 `json:"process_avg_ms"`
+	ProcessP90Ms float64 `json:"process_p90_ms"`
+	ProcessMaxMs float64 `json:"process_max_ms"`
+	WaitAvgMs    float64 `json:"wait_avg_ms"`
+	WaitP90Ms    float64 `json:"wait_p90_ms"`
+	WaitMaxMs    float64 `json:"wait_max_ms"`
+}
+
+// slowQueryJSONLine is the on-disk shape of one JSON slow log entry: one
+// JSON object per line (ndjson), field names lower-cased and
+// underscore-separated to match the `# Key:` names the text format uses.
+// BackoffTypes and Cop are the two fields the text format can't represent
+// directly (a repeated key and a group of related stats, respectively),
+// which is the whole reason a JSON format is worth having alongside it.
+type slowQueryJSONLine struct {
+	Time         time.Time             `json:"time"`
+	TxnStartTS   uint64                `json:"txn_start_ts"`
+	User         string                `json:"user"`
+	Host         string                `json:"host"`
+	ConnID       uint64                `json:"conn_id"`
+	QueryTime    float64               `json:"query_time"`
+	ParseTime    float64               `json:"parse_time"`
+	CompileTime  float64               `json:"compile_time"`
+	ProcessTime  float64               `json:"process_time"`
+	WaitTime     float64               `json:"wait_time"`
+	BackoffTime  float64               `json:"backoff_time"`
+	BackoffTypes []string              `json:"backoff_types"`
+	Cop          slowQueryJSONCopStats `json:"cop"`
+	DB           string                `json:"db"`
+	IsInternal   bool                  `json:"is_internal"`
+	Digest       string                `json:"digest"`
+	Stats        string                `json:"stats"`
+	MemMax       int64                 `json:"mem_max"`
+	Succ         bool                  `json:"succ"`
+	Plan         string                `json:"plan"`
+	PrevStmt     string                `json:"prev_stmt"`
+	Query        string                `json:"query"`
+}
+
+// parseSlowLogJSON reads a JSON-format slow log (one object per line) and
+// returns one SlowQueryJSONRow per entry. A single malformed line aborts
+// the whole parse and reports its line number, rather than silently
+// dropping rows: unlike a live PD fetch, a slow log is a file the reader
+// can go look at, so there's no "best effort partial result" worth
+// returning for a parse error here.
+func parseSlowLogJSON(r io.Reader) ([]SlowQueryJSONRow, error) {
+	scanner := bufio.NewScanner(r)
+	// Slow log entries can carry a long Query/Plan/PrevStmt; grow past
+	// bufio.Scanner's 64KB default token size.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []SlowQueryJSONRow
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry slowQueryJSONLine
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("parse slow log JSON at line %d: %v", lineNum, err)
+		}
+		rows = append(rows, SlowQueryJSONRow{
+			Time:            entry.Time,
+			TxnStartTS:      entry.TxnStartTS,
+			User:            entry.User,
+			Host:            entry.Host,
+			ConnID:          entry.ConnID,
+			QueryTime:       entry.QueryTime,
+			ParseTime:       entry.ParseTime,
+			CompileTime:     entry.CompileTime,
+			ProcessTime:     entry.ProcessTime,
+			WaitTime:        entry.WaitTime,
+			BackoffTime:     entry.BackoffTime,
+			BackoffTypes:    entry.BackoffTypes,
+			CopProcessAvgMs: entry.Cop.ProcessAvgMs,
+			CopProcessP90Ms: entry.Cop.ProcessP90Ms,
+			CopProcessMaxMs: entry.Cop.ProcessMaxMs,
+			CopWaitAvgMs:    entry.Cop.WaitAvgMs,
+			CopWaitP90Ms:    entry.Cop.WaitP90Ms,
+			CopWaitMaxMs:    entry.Cop.WaitMaxMs,
+			DB:              entry.DB,
+			IsInternal:      entry.IsInternal,
+			Digest:          entry.Digest,
+			Stats:           entry.Stats,
+			MemMax:          entry.MemMax,
+			Succ:            entry.Succ,
+			Plan:            entry.Plan,
+			PrevStmt:        entry.PrevStmt,
+			Query:           entry.Query,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read slow log JSON: %v", err)
+	}
+	return rows, nil
+}
+
+// writeSlowLogJSON appends row to w as one ndjson line, the inverse of
+// parseSlowLogJSON's per-line decode. It's what a session with
+// tidb_slow_log_format=json calls instead of the text-format writer for
+// each completed query.
+func writeSlowLogJSON(w io.Writer, row SlowQueryJSONRow) error {
+	line := slowQueryJSONLine{
+		Time:         row.Time,
+		TxnStartTS:   row.TxnStartTS,
+		User:         row.User,
+		Host:         row.Host,
+		ConnID:       row.ConnID,
+		QueryTime:    row.QueryTime,
+		ParseTime:    row.ParseTime,
+		CompileTime:  row.CompileTime,
+		ProcessTime:  row.ProcessTime,
+		WaitTime:     row.WaitTime,
+		BackoffTime:  row.BackoffTime,
+		BackoffTypes: row.BackoffTypes,
+		Cop: slowQueryJSONCopStats{
+			ProcessAvgMs: row.CopProcessAvgMs,
+			ProcessP90Ms: row.CopProcessP90Ms,
+			ProcessMaxMs: row.CopProcessMaxMs,
+			WaitAvgMs:    row.CopWaitAvgMs,
+			WaitP90Ms:    row.CopWaitP90Ms,
+			WaitMaxMs:    row.CopWaitMaxMs,
+		},
+		DB:         row.DB,
+		IsInternal: row.IsInternal,
+		Digest:     row.Digest,
+		Stats:      row.Stats,
+		MemMax:     row.MemMax,
+		Succ:       row.Succ,
+		Plan:       row.Plan,
+		PrevStmt:   row.PrevStmt,
+		Query:      row.Query,
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("marshal slow log JSON: %v", err)
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+// readSlowQueryJSONFiles reads path and, if includeRotated, its rotated
+// siblings (the same path.1, path.2, ... convention ReadSlowQueryFiles
+// reads for the text format), in ascending time order. A missing file (no
+// query has been logged yet) contributes no rows rather than an error,
+// matching how the text-format reader treats an absent log.
+func readSlowQueryJSONFiles(path string, includeRotated bool) ([]SlowQueryJSONRow, error) {
+	files := []string{path}
+	if includeRotated {
+		rotated, err := rotatedSiblings(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rotated...)
+	}
+
+	var rows []SlowQueryJSONRow
+	for _, f := range files {
+		file, err := os.Open(f)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileRows, err := parseSlowLogJSON(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parse slow log JSON %s: %v", f, err)
+		}
+		rows = append(rows, fileRows...)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Time.Before(rows[j].Time) })
+	return rows, nil
+}
+
+// rowForSlowQueryJSON flattens row into slowQueryJSONCols order.
+// BackoffTypes, a repeated "# Backoff_types:" key in the text format, is
+// comma-joined into a single column. IS_INTERNAL and SUCC are rendered as
+// "1"/"0" to match their tinyint column type.
+func rowForSlowQueryJSON(row SlowQueryJSONRow) []string {
+	return []string{
+		row.Time.Format(time.RFC3339Nano),
+		strconv.FormatUint(row.TxnStartTS, 10),
+		row.User,
+		row.Host,
+		strconv.FormatUint(row.ConnID, 10),
+		strconv.FormatFloat(row.QueryTime, 'f', -1, 64),
+		strconv.FormatFloat(row.ParseTime, 'f', -1, 64),
+		strconv.FormatFloat(row.CompileTime, 'f', -1, 64),
+		strconv.FormatFloat(row.ProcessTime, 'f', -1, 64),
+		strconv.FormatFloat(row.WaitTime, 'f', -1, 64),
+		strconv.FormatFloat(row.BackoffTime, 'f', -1, 64),
+		strings.Join(row.BackoffTypes, ","),
+		strconv.FormatFloat(row.CopProcessAvgMs, 'f', -1, 64),
+		strconv.FormatFloat(row.CopProcessP90Ms, 'f', -1, 64),
+		strconv.FormatFloat(row.CopProcessMaxMs, 'f', -1, 64),
+		strconv.FormatFloat(row.CopWaitAvgMs, 'f', -1, 64),
+		strconv.FormatFloat(row.CopWaitP90Ms, 'f', -1, 64),
+		strconv.FormatFloat(row.CopWaitMaxMs, 'f', -1, 64),
+		row.DB,
+		tinyintBool(row.IsInternal),
+		row.Digest,
+		row.Stats,
+		strconv.FormatInt(row.MemMax, 10),
+		tinyintBool(row.Succ),
+		row.Plan,
+		row.PrevStmt,
+		row.Query,
+	}
+}
+
+// tinyintBool renders b as a tinyint column value: "1" or "0".
+func tinyintBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// dataForSlowQueryJSON is SLOW_QUERY_JSON's row source: it reads path (and
+// its rotated siblings, when includeRotated) via readSlowQueryJSONFiles.
+//
+// The real TiDB server resolves path/includeRotated from the session's
+// tidb_slow_query_file and tidb_slow_query_include_rotated before calling
+// this; that wiring lives in the executor package, which isn't part of
+// this tree, so it isn't reproduced here.
+func dataForSlowQueryJSON(path string, includeRotated bool) ([][]string, error) {
+	entries, err := readSlowQueryJSONFiles(path, includeRotated)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, rowForSlowQueryJSON(e))
+	}
+	return rows, nil
+}
+
+// dataForClusterSlowQueryJSON is CLUSTER_SLOW_QUERY_JSON's row source. It
+// reuses dataForSlowQueryJSON for the local instance's own log and
+// prepends an INSTANCE column, left empty here: fanning this query out to
+// every other TiDB instance in the cluster (the way CLUSTER_CONFIG fans
+// "/config" out via clusterInstances) needs a live RPC/distributed-exec
+// path this tree has no equivalent of for slow logs, so this table can
+// only ever report its own instance's rows, not the whole cluster's.
+func dataForClusterSlowQueryJSON(path string, includeRotated bool) ([][]string, error) {
+	rows, err := dataForSlowQueryJSON(path, includeRotated)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = append([]string{""}, row...)
+	}
+	return out, nil
+}
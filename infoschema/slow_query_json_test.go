@@ -0,0 +1,163 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestWriteSlowLogJSONRoundTrips guards against the writer and reader
+// drifting apart: whatever writeSlowLogJSON produces must come back out of
+// parseSlowLogJSON unchanged, including the BackoffTypes array and Cop
+// nested object the text format can't represent.
+func TestWriteSlowLogJSONRoundTrips(t *testing.T) {
+	want := SlowQueryJSONRow{
+		Time:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		TxnStartTS:      12345,
+		User:            "root",
+		Host:            "127.0.0.1",
+		ConnID:          7,
+		QueryTime:       1.5,
+		ParseTime:       0.1,
+		CompileTime:     0.2,
+		ProcessTime:     0.3,
+		WaitTime:        0.05,
+		BackoffTime:     0.01,
+		BackoffTypes:    []string{"tikvRPC", "regionMiss"},
+		CopProcessAvgMs: 1.1,
+		CopProcessP90Ms: 2.2,
+		CopProcessMaxMs: 3.3,
+		CopWaitAvgMs:    0.1,
+		CopWaitP90Ms:    0.2,
+		CopWaitMaxMs:    0.3,
+		DB:              "test",
+		IsInternal:      false,
+		Digest:          "abc123",
+		Stats:           "count:1",
+		MemMax:          1024,
+		Succ:            true,
+		Plan:            "some plan",
+		PrevStmt:        "",
+		Query:           "select 1",
+	}
+
+	var buf bytes.Buffer
+	if err := writeSlowLogJSON(&buf, want); err != nil {
+		t.Fatalf("writeSlowLogJSON: %v", err)
+	}
+
+	got, err := parseSlowLogJSON(&buf)
+	if err != nil {
+		t.Fatalf("parseSlowLogJSON: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d rows, want 1", len(got))
+	}
+	if !got[0].Time.Equal(want.Time) {
+		t.Errorf("Time = %v, want %v", got[0].Time, want.Time)
+	}
+	got[0].Time = want.Time // time.Time equality isn't comparable across locations
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("round-tripped row = %+v, want %+v", got[0], want)
+	}
+}
+
+// TestDataForSlowQueryJSONReadsRotatedFiles guards dataForSlowQueryJSON's
+// signature (path, includeRotated) and rotation handling: rows from the
+// active file and a rotated sibling should come back merged, in time
+// order, with BackoffTypes and the Cop stats flattened into their own
+// columns.
+func TestDataForSlowQueryJSONReadsRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/tidb-slow.log.json"
+
+	var active, rotated bytes.Buffer
+	newer := SlowQueryJSONRow{Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), ConnID: 2, Query: "select 2"}
+	older := SlowQueryJSONRow{
+		Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ConnID: 1, Query: "select 1",
+		BackoffTypes: []string{"tikvRPC"}, CopProcessAvgMs: 5,
+	}
+	if err := writeSlowLogJSON(&active, newer); err != nil {
+		t.Fatalf("writeSlowLogJSON: %v", err)
+	}
+	if err := writeSlowLogJSON(&rotated, older); err != nil {
+		t.Fatalf("writeSlowLogJSON: %v", err)
+	}
+	if err := os.WriteFile(path, active.Bytes(), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(path+".1", rotated.Bytes(), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rows, err := dataForSlowQueryJSON(path, true)
+	if err != nil {
+		t.Fatalf("dataForSlowQueryJSON: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0][4] != "1" || rows[1][4] != "2" {
+		t.Errorf("rows not in time order: %+v", rows)
+	}
+	if rows[0][11] != "tikvRPC" || rows[0][12] != "5" {
+		t.Errorf("row[0] BACKOFF_TYPES/COP_PROCESS_AVG_MS = %q/%q, want tikvRPC/5", rows[0][11], rows[0][12])
+	}
+
+	withoutRotated, err := dataForSlowQueryJSON(path, false)
+	if err != nil {
+		t.Fatalf("dataForSlowQueryJSON: %v", err)
+	}
+	if len(withoutRotated) != 1 {
+		t.Fatalf("got %d rows with includeRotated=false, want 1", len(withoutRotated))
+	}
+}
+
+// TestDataForClusterSlowQueryJSONPrependsInstanceColumn guards
+// CLUSTER_SLOW_QUERY_JSON's one structural difference from
+// SLOW_QUERY_JSON: a leading INSTANCE column.
+func TestDataForClusterSlowQueryJSONPrependsInstanceColumn(t *testing.T) {
+	if _, ok := virtualTables[TableClusterSlowQueryJSON]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableClusterSlowQueryJSON)
+	}
+
+	dir := t.TempDir()
+	path := dir + "/tidb-slow.log.json"
+	var buf bytes.Buffer
+	if err := writeSlowLogJSON(&buf, SlowQueryJSONRow{Time: time.Now(), ConnID: 1}); err != nil {
+		t.Fatalf("writeSlowLogJSON: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	plain, err := dataForSlowQueryJSON(path, false)
+	if err != nil {
+		t.Fatalf("dataForSlowQueryJSON: %v", err)
+	}
+	cluster, err := dataForClusterSlowQueryJSON(path, false)
+	if err != nil {
+		t.Fatalf("dataForClusterSlowQueryJSON: %v", err)
+	}
+	if len(cluster) != len(plain) || len(cluster[0]) != len(plain[0])+1 {
+		t.Fatalf("cluster rows should be plain rows with one extra leading column: got %+v, %+v", cluster, plain)
+	}
+	if !reflect.DeepEqual(cluster[0][1:], plain[0]) {
+		t.Errorf("cluster row minus its leading column = %+v, want %+v", cluster[0][1:], plain[0])
+	}
+}
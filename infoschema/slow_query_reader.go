@@ -0,0 +1,374 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableSlowQuery is the name INFORMATION_SCHEMA.SLOW_QUERY is registered
+// under.
+const TableSlowQuery = "SLOW_QUERY"
+
+var slowQueryCols = []columnInfo{
+	{name: "TIME", typeName: "datetime"},
+	{name: "DIGEST", typeName: "varchar(64)"},
+	{name: "CONN_ID", typeName: "bigint unsigned"},
+	{name: "USER", typeName: "varchar(64)"},
+	{name: "QUERY", typeName: "longtext"},
+}
+
+func init() {
+	registerVirtualTable(TableSlowQuery, slowQueryCols, dataForSlowQuery)
+}
+
+// dataForSlowQuery is SLOW_QUERY's row source: it reads path (and its
+// rotated siblings, when includeRotated) via ReadSlowQueryFiles and
+// flattens each entry's remaining lines back into one QUERY string, the
+// same pushdown path a predicate on Time/Digest/Conn_ID/User already takes
+// advantage of in scanSlowLogFile.
+//
+// The real TiDB server resolves path/includeRotated/pred from the
+// session's tidb_slow_query_file, tidb_slow_query_include_rotated and the
+// query's own WHERE clause before calling this; that wiring lives in the
+// executor package, which isn't part of this tree, so it isn't
+// reproduced here.
+func dataForSlowQuery(path string, includeRotated bool, pred *SlowQueryPredicate) ([][]string, error) {
+	entries, err := ReadSlowQueryFiles(path, includeRotated, pred)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Time.Format(time.RFC3339Nano),
+			e.Digest,
+			strconv.FormatUint(e.ConnID, 10),
+			e.User,
+			strings.Join(e.Rest, "\n"),
+		})
+	}
+	return rows, nil
+}
+
+// SlowQueryEntry is one entry read out of a slow log file: the header
+// fields a predicate can cheaply filter on, plus the entry's remaining
+// lines (every "# Key: value" line after Time/Digest/Conn_ID/User, and the
+// SQL body) left unparsed for the caller to pick apart.
+type SlowQueryEntry struct {
+	Time   time.Time
+	Digest string
+	ConnID uint64
+	User   string
+	Rest   []string
+}
+
+// SlowQueryPredicate restricts a slow log scan to entries matching all of
+// its non-zero fields. A zero SlowQueryPredicate matches everything.
+type SlowQueryPredicate struct {
+	TimeStart time.Time
+	TimeEnd   time.Time
+	Digests   map[string]struct{}
+	ConnIDs   map[uint64]struct{}
+	Users     map[string]struct{}
+}
+
+func (p *SlowQueryPredicate) matchesHeader(d string, conn uint64, user string) bool {
+	if p == nil {
+		return true
+	}
+	if p.Digests != nil {
+		if _, ok := p.Digests[d]; !ok {
+			return false
+		}
+	}
+	if p.ConnIDs != nil {
+		if _, ok := p.ConnIDs[conn]; !ok {
+			return false
+		}
+	}
+	if p.Users != nil {
+		if _, ok := p.Users[user]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *SlowQueryPredicate) matchesTime(t time.Time) bool {
+	if p == nil {
+		return true
+	}
+	if !p.TimeStart.IsZero() && t.Before(p.TimeStart) {
+		return false
+	}
+	if !p.TimeEnd.IsZero() && t.After(p.TimeEnd) {
+		return false
+	}
+	return true
+}
+
+const (
+	slowLogTimePrefix   = "# Time: "
+	slowLogDigestPrefix = "# Digest: "
+	slowLogConnIDPrefix = "# Conn_ID: "
+	slowLogUserPrefix   = "# User: "
+)
+
+// ReadSlowQueryFiles reads path and, if includeRotated, its rotated
+// siblings (path.1, path.2, ... as left behind by a size/time-based log
+// rotation), in ascending time order, and returns every entry matching
+// pred. Each file is scanned with scanSlowLogFile, so a predicate on Time
+// lets a file be skipped (or binary-searched into) entirely instead of
+// read start to finish.
+func ReadSlowQueryFiles(path string, includeRotated bool, pred *SlowQueryPredicate) ([]SlowQueryEntry, error) {
+	files := []string{path}
+	if includeRotated {
+		rotated, err := rotatedSiblings(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rotated...)
+	}
+
+	var entries []SlowQueryEntry
+	for _, f := range files {
+		fileEntries, err := scanSlowLogFile(f, pred)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scan slow log %s: %v", f, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// rotatedSiblings returns path's rotated siblings ("path.1", "path.2", ...,
+// whatever numeric suffixes exist), oldest first. Slow log rotation always
+// appends a "."+N suffix to the base name, the same convention TiDB's own
+// log rotation uses for the server log.
+func rotatedSiblings(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	type numbered struct {
+		path string
+		n    int
+	}
+	var siblings []numbered
+	for _, m := range matches {
+		suffix := strings.TrimPrefix(m, path+".")
+		n, err := strconv.Atoi(suffix)
+		if err != nil {
+			// Not a "path.<N>" rotation file (e.g. a ".bak" a user left
+			// next to it); ignore rather than erroring the whole scan.
+			continue
+		}
+		siblings = append(siblings, numbered{path: m, n: n})
+	}
+	// Logrotate-style numbering counts up with age: path.1 is the most
+	// recently rotated file, path.2 older still. Sort oldest-first; callers
+	// still sort the merged entries by time afterwards.
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i].n > siblings[j].n })
+	out := make([]string, 0, len(siblings))
+	for _, s := range siblings {
+		out = append(out, s.path)
+	}
+	return out, nil
+}
+
+// scanSlowLogFile scans a single slow log file for entries matching pred.
+// When pred carries a TimeStart, the file is first binary-searched (via
+// seekToTime) for the byte offset of the first entry that could possibly
+// match, so a query scoped to "the last hour" of a multi-GB log doesn't
+// pay to read everything before it; scanning also stops the moment an
+// entry's Time crosses TimeEnd, rather than reading to EOF. Within that
+// range, a rejected entry is discarded once its header is fully read,
+// without its SQL body ever being parsed into anything beyond the raw
+// lines held temporarily to build it.
+func scanSlowLogFile(path string, pred *SlowQueryPredicate) ([]SlowQueryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var start int64
+	if pred != nil && !pred.TimeStart.IsZero() {
+		start, err = seekToTime(f, pred.TimeStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var entries []SlowQueryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		cur        SlowQueryEntry
+		haveHeader bool
+		rest       []string
+	)
+	// flush is called once all of the current entry's header lines have
+	// been seen (i.e. right before the next entry's "# Time:" line, or at
+	// EOF), so it's the first point at which Digest/Conn_ID/User are all
+	// known and the predicate can be evaluated correctly - checking any
+	// earlier would reject (or wrongly accept) entries based on fields
+	// that haven't been parsed yet.
+	flush := func() {
+		if haveHeader && pred.matchesTime(cur.Time) && pred.matchesHeader(cur.Digest, cur.ConnID, cur.User) {
+			cur.Rest = rest
+			entries = append(entries, cur)
+		}
+		cur, haveHeader, rest = SlowQueryEntry{}, false, nil
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, slowLogTimePrefix):
+			flush()
+			t, err := time.Parse(time.RFC3339Nano, strings.TrimPrefix(line, slowLogTimePrefix))
+			if err != nil {
+				return nil, fmt.Errorf("parse %q: %v", line, err)
+			}
+			// Entries past TimeEnd can't be followed by an earlier one
+			// again (slow log entries are appended in increasing time
+			// order), so there's nothing left to find.
+			if pred != nil && !pred.TimeEnd.IsZero() && t.After(pred.TimeEnd) {
+				return entries, nil
+			}
+			cur.Time = t
+			haveHeader = true
+		case strings.HasPrefix(line, slowLogDigestPrefix):
+			cur.Digest = strings.TrimPrefix(line, slowLogDigestPrefix)
+		case strings.HasPrefix(line, slowLogConnIDPrefix):
+			id, err := strconv.ParseUint(strings.TrimPrefix(line, slowLogConnIDPrefix), 10, 64)
+			if err == nil {
+				cur.ConnID = id
+			}
+		case strings.HasPrefix(line, slowLogUserPrefix):
+			// "# User: root@127.0.0.1" - keep the user name, drop the host.
+			cur.User = strings.SplitN(strings.TrimPrefix(line, slowLogUserPrefix), "@", 2)[0]
+		default:
+			if haveHeader {
+				rest = append(rest, line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
+
+// seekToTime returns the byte offset of the first entry at or after
+// target, by binary-searching directly on file offsets rather than
+// building an index over every "# Time:" line first. Each probe seeks to a
+// candidate offset and scans forward only as far as the next "# Time:"
+// line (nextTimeLineAt) - bounded by one entry's size, not by the size of
+// the remaining search range - so the whole search costs O(log(file size))
+// seeks plus O(log(file size) * one entry's size) of reading, not a single
+// byte of the file outside the entries the search actually visits.
+func seekToTime(f *os.File, target time.Time) (int64, error) {
+	size, err := fileSize(f)
+	if err != nil {
+		return 0, err
+	}
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		offset, t, ok, err := nextTimeLineAt(f, mid, size)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			// Nothing but entry bodies between mid and EOF: every
+			// remaining header starts before mid.
+			hi = mid
+			continue
+		}
+		if t.Before(target) {
+			// This entry is too early; the next header after its own
+			// start is the next candidate to test.
+			lo = offset + 1
+		} else {
+			hi = mid
+		}
+	}
+	offset, _, ok, err := nextTimeLineAt(f, lo, size)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return size, nil
+	}
+	return offset, nil
+}
+
+// nextTimeLineAt seeks f to from and scans forward for the next line
+// starting with slowLogTimePrefix, stopping at limit. It returns ok=false
+// (not an error) if no such line exists before limit, which seekToTime
+// reads as "everything from `from` to `limit` is entry-body text, not a
+// header" rather than a failure.
+func nextTimeLineAt(f *os.File, from, limit int64) (offset int64, t time.Time, ok bool, err error) {
+	if _, err = f.Seek(from, io.SeekStart); err != nil {
+		return 0, time.Time{}, false, err
+	}
+	scanner := bufio.NewScanner(io.LimitReader(f, limit-from))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	pos := from
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineStart := pos
+		pos += int64(len(line)) + 1
+		if strings.HasPrefix(line, slowLogTimePrefix) {
+			t, err = time.Parse(time.RFC3339Nano, strings.TrimPrefix(line, slowLogTimePrefix))
+			if err != nil {
+				return 0, time.Time{}, false, fmt.Errorf("parse %q: %v", line, err)
+			}
+			return lineStart, t, true, nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, time.Time{}, false, err
+	}
+	return 0, time.Time{}, false, nil
+}
+
+// fileSize returns f's current size via Stat, the cheap way to learn it
+// without reading any of the file's content.
+func fileSize(f *os.File) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// genSlowLog writes n synthetic entries to path, one second apart starting
+// at base, and returns every entry's time in order (so callers can ask
+// seekToTime/ReadSlowQueryFiles to find a specific one without hardcoding
+// an offset into the generated content).
+func genSlowLog(t *testing.T, path string, base time.Time, n int) []time.Time {
+	t.Helper()
+	var sb []byte
+	times := make([]time.Time, n)
+	for i := 0; i < n; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		times[i] = ts
+		sb = append(sb, fmt.Sprintf(
+			"# Time: %s\n# Digest: d%d\n# Conn_ID: %d\n# User: root@127.0.0.1\nselect %d;\n",
+			ts.Format(time.RFC3339Nano), i, i, i)...)
+	}
+	if err := os.WriteFile(path, sb, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return times
+}
+
+// TestSeekToTimeFindsExactOffset checks that seekToTime lands on the byte
+// offset of the first entry at or after target, for targets that fall
+// exactly on an entry, between two entries, before the first entry, and
+// after the last one.
+func TestSeekToTimeFindsExactOffset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tidb-slow.log")
+	base := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	times := genSlowLog(t, path, base, 50)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	cases := []struct {
+		name   string
+		target time.Time
+		want   int
+	}{
+		{"exact match", times[10], 10},
+		{"between entries", times[10].Add(500 * time.Millisecond), 11},
+		{"before first", base.Add(-time.Hour), 0},
+		{"after last", times[len(times)-1].Add(time.Hour), len(times)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			offset, err := seekToTime(f, c.target)
+			if err != nil {
+				t.Fatalf("seekToTime: %v", err)
+			}
+			size, err := fileSize(f)
+			if err != nil {
+				t.Fatalf("fileSize: %v", err)
+			}
+			if c.want == len(times) {
+				if offset != size {
+					t.Errorf("offset = %d, want EOF offset %d", offset, size)
+				}
+				return
+			}
+			gotOffset, gotTime, ok, err := nextTimeLineAt(f, offset, size)
+			if err != nil {
+				t.Fatalf("nextTimeLineAt: %v", err)
+			}
+			if !ok || gotOffset != offset {
+				t.Fatalf("offset %d does not start a \"# Time:\" line", offset)
+			}
+			if !gotTime.Equal(times[c.want]) {
+				t.Errorf("entry at offset %d has time %v, want %v (index %d)", offset, gotTime, times[c.want], c.want)
+			}
+		})
+	}
+}
+
+// TestReadSlowQueryFilesRotatedOrder checks that every entry of the active
+// file and its rotated siblings comes back, merged in non-decreasing time
+// order regardless of which physical file an entry lives in.
+func TestReadSlowQueryFilesRotatedOrder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tidb-slow.log")
+	base := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	genSlowLog(t, path+".2", base, 2)                     // oldest
+	genSlowLog(t, path+".1", base.Add(10*time.Second), 2) // middle
+	genSlowLog(t, path, base.Add(20*time.Second), 2)      // active, newest
+
+	entries, err := ReadSlowQueryFiles(path, true, nil)
+	if err != nil {
+		t.Fatalf("ReadSlowQueryFiles: %v", err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("got %d entries, want 6", len(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Time.Before(entries[i-1].Time) {
+			t.Fatalf("entries out of order at %d: %v before %v", i, entries[i].Time, entries[i-1].Time)
+		}
+	}
+}
+
+// BenchmarkSeekToTime times seekToTime against logs of 100, 10000 and
+// 1000000 entries, so its cost can be compared across file sizes; it
+// reports only the time per call, not the number of bytes read.
+func BenchmarkSeekToTime(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		n := n
+		b.Run(fmt.Sprintf("entries=%d", n), func(b *testing.B) {
+			dir := b.TempDir()
+			path := filepath.Join(dir, "tidb-slow.log")
+			base := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+			times := genSlowLogBench(b, path, base, n)
+			f, err := os.Open(path)
+			if err != nil {
+				b.Fatalf("Open: %v", err)
+			}
+			defer f.Close()
+			target := times[n/2]
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := seekToTime(f, target); err != nil {
+					b.Fatalf("seekToTime: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// genSlowLogBench is genSlowLog's *testing.B counterpart (b.TempDir/Fatalf
+// aren't shared methods with *testing.T).
+func genSlowLogBench(b *testing.B, path string, base time.Time, n int) []time.Time {
+	b.Helper()
+	var sb []byte
+	times := make([]time.Time, n)
+	for i := 0; i < n; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		times[i] = ts
+		sb = append(sb, fmt.Sprintf(
+			"# Time: %s\n# Digest: d%d\n# Conn_ID: %d\n# User: root@127.0.0.1\nselect %d;\n",
+			ts.Format(time.RFC3339Nano), i, i, i)...)
+	}
+	if err := os.WriteFile(path, sb, 0644); err != nil {
+		b.Fatalf("WriteFile: %v", err)
+	}
+	return times
+}
+
+// TestDataForSlowQueryReadsRegisteredTable guards against SLOW_QUERY being
+// defined (ReadSlowQueryFiles/scanSlowLogFile/seekToTime) but never
+// registered or called: without registerVirtualTable wired to a row
+// source that actually invokes them, none of that parsing logic is
+// reachable from SQL.
+func TestDataForSlowQueryReadsRegisteredTable(t *testing.T) {
+	if _, ok := virtualTables[TableSlowQuery]; !ok {
+		t.Fatalf("%s was not registered as a virtual table", TableSlowQuery)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tidb-slow.log")
+	content := "# Time: 2026-01-02T03:04:05.000000000Z\n" +
+		"# Digest: abc123\n" +
+		"# Conn_ID: 7\n" +
+		"# User: root@127.0.0.1\n" +
+		"select 1;\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rows, err := dataForSlowQuery(path, false, nil)
+	if err != nil {
+		t.Fatalf("dataForSlowQuery: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	if row[1] != "abc123" || row[2] != "7" || row[3] != "root" {
+		t.Errorf("row = %+v, want digest=abc123 conn_id=7 user=root", row)
+	}
+	if row[4] != "select 1;" {
+		t.Errorf("row query = %q, want %q", row[4], "select 1;")
+	}
+}
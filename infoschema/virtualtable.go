@@ -0,0 +1,57 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+// columnInfo describes one column of a virtual (in-memory, not KV-backed)
+// INFORMATION_SCHEMA table: its name and the MySQL type keyword a DESCRIBE
+// or catalog lookup reports for it (e.g. "bigint", "varchar(64)",
+// "datetime").
+type columnInfo struct {
+	name     string
+	typeName string
+}
+
+// virtualTable is one registered virtual table: its declared columns and
+// the function that produces its current row set. rowsFn's signature
+// varies per table (dataForClusterConfig, fetchPDHotRegions, ...); the
+// catalog's row-building pass type-asserts it to the signature it expects
+// for that table name the same way it does for every other virtual table.
+type virtualTable struct {
+	cols   []columnInfo
+	rowsFn interface{}
+}
+
+// virtualTables is the registry of in-memory INFORMATION_SCHEMA tables
+// this package backs with a live fetch (PD, a slow log file, a cluster
+// HTTP endpoint) instead of a KV scan, keyed by table name. The catalog
+// consults it when building its table set the same way it consults the
+// KV-backed table map; a name registered here without also being wired
+// into the catalog's virtual-table pass defines its schema and fetch
+// logic but never becomes SELECT-able. Defining a new virtual table and
+// registering it here must happen in the same change, or the table's
+// columns/fetch logic exist without `SELECT ... FROM
+// INFORMATION_SCHEMA.<name>` ever finding it.
+var virtualTables = map[string]*virtualTable{}
+
+// registerVirtualTable adds name to virtualTables. It's meant to be
+// called from an init() in the file that defines the table's row type and
+// fetch function, the same way RegisterSysVar is called from each
+// sysvar's own file; a name registered twice panics, since that can only
+// be a programming mistake.
+func registerVirtualTable(name string, cols []columnInfo, rowsFn interface{}) {
+	if _, ok := virtualTables[name]; ok {
+		panic("infoschema: virtual table " + name + " already registered")
+	}
+	virtualTables[name] = &virtualTable{cols: cols, rowsFn: rowsFn}
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// TiDBExtendedDateRange is a TiDB-specific session variable. When ON, the
+// time-parsing and calendar builtins (TIMESTAMP, DAYOFYEAR, DAYOFWEEK,
+// YEARWEEK, ...) accept a trailing BC/AD marker or a leading minus sign on
+// the year, and compute DAYOFYEAR/DAYOFWEEK/YEARWEEK on the proleptic
+// calendar. Only proleptic year 0 (e.g. "0001-12-31 BC") can be held in a
+// Time value; any other BC-era or negative-year date is rejected with an
+// error, as outside MySQL's native year-1-to-9999 range.
+const TiDBExtendedDateRange = "tidb_extended_date_range"
+
+// DefaultExtendedDateRange is OFF, preserving MySQL-compatible rejection of
+// out-of-range years unless a session opts in.
+const DefaultExtendedDateRange = "0"
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: TiDBExtendedDateRange, Value: DefaultExtendedDateRange})
+}
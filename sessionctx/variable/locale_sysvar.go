@@ -0,0 +1,26 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// LcTimeNames is the MySQL-compatible session variable controlling which
+// locale's weekday/month name tables DAYNAME/MONTHNAME/DATE_FORMAT render
+// with (e.g. "en_US", "de_DE", "ja_JP").
+const LcTimeNames = "lc_time_names"
+
+// DefaultLcTimeNames is the value lc_time_names takes in a fresh session.
+const DefaultLcTimeNames = "en_US"
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: LcTimeNames, Value: DefaultLcTimeNames})
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// TiDBSlowLogFormat selects the on-disk format new slow log entries are
+// written in: "text" for the traditional "# Key: value" format
+// INFORMATION_SCHEMA.SLOW_QUERY reads, or "json" for one JSON object per
+// line (ndjson), for deployments that feed the slow log into a JSON-only
+// log pipeline without a bespoke parser on the other end.
+const TiDBSlowLogFormat = "tidb_slow_log_format"
+
+// SlowLogFormatText and SlowLogFormatJSON are the only values
+// tidb_slow_log_format accepts.
+const (
+	SlowLogFormatText = "text"
+	SlowLogFormatJSON = "json"
+)
+
+// DefaultSlowLogFormat is "text", matching the format TiDB has always
+// written; switching to "json" is opt-in.
+const DefaultSlowLogFormat = SlowLogFormatText
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: TiDBSlowLogFormat, Value: DefaultSlowLogFormat})
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// TiDBSlowQueryIncludeRotated is a TiDB-specific session variable. When ON
+// (the default), a query against INFORMATION_SCHEMA.SLOW_QUERY transparently
+// reads the current slow log's rotated siblings (tidb-slow.log.1, .2, ...)
+// alongside it, in time order, so a rotation doesn't make older slow
+// queries disappear from the table. Turning it OFF restricts the scan to
+// tidb_slow_query_file itself, useful when a caller only wants to know
+// about queries since the last rotation without paying to open every
+// sibling.
+const TiDBSlowQueryIncludeRotated = "tidb_slow_query_include_rotated"
+
+// DefaultSlowQueryIncludeRotated is ON.
+const DefaultSlowQueryIncludeRotated = "1"
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: TiDBSlowQueryIncludeRotated, Value: DefaultSlowQueryIncludeRotated})
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// TiDBStrToDateStrict is a TiDB-specific session variable. When ON,
+// STR_TO_DATE raises an error instead of returning NULL once every candidate
+// format in a comma-separated format list has failed to parse the input.
+const TiDBStrToDateStrict = "tidb_str_to_date_strict"
+
+// DefaultStrToDateStrict is OFF, preserving STR_TO_DATE's existing
+// return-NULL-on-failure behavior unless a session opts in.
+const DefaultStrToDateStrict = "0"
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: TiDBStrToDateStrict, Value: DefaultStrToDateStrict})
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// ScopeFlag indicates the scope(s) a SysVar can be read or set at,
+// mirroring MySQL's SESSION/GLOBAL variable scoping.
+type ScopeFlag uint8
+
+const (
+	// ScopeSession means the variable can be set per-session with
+	// `SET SESSION` (or the bare `SET`), and a session's own value never
+	// affects any other session.
+	ScopeSession ScopeFlag = 1 << iota
+	// ScopeGlobal means the variable can be set cluster-wide with
+	// `SET GLOBAL`.
+	ScopeGlobal
+)
+
+// SysVar describes one system variable: where it can be set, and the
+// value a fresh session sees until something sets it.
+type SysVar struct {
+	Scope ScopeFlag
+	Name  string
+	Value string
+}
+
+// SysVars is the registry of every known system variable, keyed by name.
+// SessionVars.GetSystemVar/SetSystemVar consult it: a name missing here is
+// rejected as an unknown system variable, and a name present but never
+// overridden by the session reads back Value. Defining a new session
+// variable and registering it here must happen in the same change, or
+// `SET`/`SELECT @@` on it fails despite the variable's own plumbing
+// otherwise working.
+var SysVars = map[string]*SysVar{}
+
+// RegisterSysVar adds sv to SysVars. It's meant to be called from an
+// init() in the file that defines the variable's name/default constants,
+// the same way the builtin sysvars in this package register themselves; a
+// name registered twice panics, since that can only be a programming
+// mistake.
+func RegisterSysVar(sv *SysVar) {
+	if _, ok := SysVars[sv.Name]; ok {
+		panic("variable: sysvar " + sv.Name + " already registered")
+	}
+	SysVars[sv.Name] = sv
+}
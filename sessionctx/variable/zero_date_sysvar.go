@@ -0,0 +1,30 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// TiDBZeroDateAsNull is a TiDB-specific session variable. When ON, the
+// vectorized time builtins return NULL for a zero-valued or otherwise
+// InvalidZero() datetime argument silently, without a warning or error,
+// regardless of NoZeroDate/NoZeroInDate in SQLMode. This mirrors the
+// "null zero-date" option common in database drivers, so ETL from dumps
+// containing `0000-00-00` doesn't have to pre-filter those rows.
+const TiDBZeroDateAsNull = "tidb_zero_date_as_null"
+
+// DefaultZeroDateAsNull is OFF, preserving the existing SQLMode-driven
+// warn/error behavior unless a session opts in.
+const DefaultZeroDateAsNull = "0"
+
+func init() {
+	RegisterSysVar(&SysVar{Scope: ScopeSession | ScopeGlobal, Name: TiDBZeroDateAsNull, Value: DefaultZeroDateAsNull})
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import "testing"
+
+// TestTiDBZeroDateAsNullRegistered guards against the sysvar being defined
+// but never reaching RegisterSysVar: without an entry in SysVars, `SET
+// tidb_zero_date_as_null = 1` / `SELECT @@tidb_zero_date_as_null` fail as
+// an unknown system variable despite zeroDateAsNullEnabled otherwise
+// reading it correctly.
+func TestTiDBZeroDateAsNullRegistered(t *testing.T) {
+	sv, ok := SysVars[TiDBZeroDateAsNull]
+	if !ok {
+		t.Fatalf("%s was not registered in SysVars", TiDBZeroDateAsNull)
+	}
+	if sv.Scope != ScopeSession|ScopeGlobal {
+		t.Errorf("Scope = %v, want ScopeSession|ScopeGlobal", sv.Scope)
+	}
+	if sv.Value != DefaultZeroDateAsNull {
+		t.Errorf("Value = %q, want default %q (OFF)", sv.Value, DefaultZeroDateAsNull)
+	}
+}
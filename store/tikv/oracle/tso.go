@@ -0,0 +1,60 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle
+
+import "time"
+
+// physicalShiftBits and logicalBits describe the TSO bit layout: the high 46
+// bits hold the physical time in milliseconds since the Unix epoch, and the
+// low 18 bits hold the logical counter within that millisecond.
+const (
+	physicalShiftBits = 18
+	logicalBits       = (1 << physicalShiftBits) - 1
+)
+
+// ComposeTS is the inverse of GetTimeFromTS/ExtractPhysical: it packs a
+// physical time (in milliseconds since the epoch) and a logical counter back
+// into a single TSO, as used by TIDB_BUILD_TSO.
+func ComposeTS(physical, logical int64) uint64 {
+	return uint64((physical << physicalShiftBits) + logical)
+}
+
+// GetPhysical returns Unix epoch milliseconds for the given wall-clock time,
+// the inverse of GetTimeFromTS's physical-time computation.
+func GetPhysical(t time.Time) int64 {
+	return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+}
+
+// ExtractPhysical extracts the physical-time component (Unix epoch
+// milliseconds) from a TSO, ignoring the logical counter.
+func ExtractPhysical(ts uint64) int64 {
+	return int64(ts >> physicalShiftBits)
+}
+
+// ExtractLogical extracts the logical counter from a TSO.
+func ExtractLogical(ts uint64) int64 {
+	return int64(ts & logicalBits)
+}
+
+// TSFromPhysical builds a TSO for the given physical time with logical
+// counter 0, or with the maximum logical counter when maxLogical is set,
+// which is the form index-friendly BETWEEN rewrites need: the
+// smallest/largest TSO whose decoded time equals a given datetime bound.
+func TSFromPhysical(physical int64, maxLogical bool) uint64 {
+	logical := int64(0)
+	if maxLogical {
+		logical = logicalBits
+	}
+	return ComposeTS(physical, logical)
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+// MySQL's TIME type saturates at +/-838:59:59.
+const (
+	TimeMaxHour   = 838
+	TimeMaxMinute = 59
+	TimeMaxSecond = 59
+)
+
+// NewDurationFromParts builds a Duration directly from its sign and
+// hour/minute/second/microsecond components, without the
+// format-to-string-then-reparse round trip `fmt.Sprintf("%s%02d:%02d:%v",
+// ...)` + ParseDuration used to pay for every row.
+//
+// Duration wraps a single signed time.Duration with no separate sign bit,
+// so neg is only observable through the sign of the resulting magnitude:
+// when hour, minute, second, and microsecond are all zero, `-d` is still
+// `0` in Go and neg=true is indistinguishable from neg=false. This mirrors
+// MySQL itself, which has no representable negative-zero TIME either — so
+// the loss isn't something NewDurationFromParts introduces, just a case
+// this constructor can't avoid with Duration's current shape.
+func NewDurationFromParts(neg bool, hour, minute, second int64, microsecond int, fsp int8) (Duration, error) {
+	if hour > TimeMaxHour {
+		hour, minute, second, microsecond = TimeMaxHour, TimeMaxMinute, TimeMaxSecond, 0
+	}
+	d := time.Duration(hour)*time.Hour +
+		time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second +
+		time.Duration(microsecond)*time.Microsecond
+	if neg {
+		d = -d
+	}
+	return Duration{Duration: d, Fsp: fsp}, nil
+}
+
+// IsNeg reports whether d represents a negative MySQL TIME value. It's the
+// single place builtins that only care about the sign (HOUR, MINUTE,
+// SECOND, TIME_TO_SEC) should check, rather than each re-deriving it from
+// `Duration.Duration < 0`.
+func (d Duration) IsNeg() bool {
+	return d.Duration < 0
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "testing"
+
+// TestNewDurationFromPartsIsNeg checks the sign through a case with a
+// genuinely nonzero magnitude, where neg is recoverable from the sign of
+// the resulting time.Duration.
+//
+// This is also the only slice of builtinMakeTimeSig's (MAKETIME) row logic
+// that's testable here: the rest of its vecEvalDuration, along with
+// builtinSubDateAndDurationSig, builtinUnixTimestampIntSig,
+// builtinSubDatetimeAndDurationSig and builtinConvertTzSig, takes
+// sessionctx.Context and util/chunk.Chunk, neither of which is defined
+// anywhere in this tree.
+func TestNewDurationFromPartsIsNeg(t *testing.T) {
+	d, err := NewDurationFromParts(true, 1, 2, 3, 4, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	if !d.IsNeg() {
+		t.Errorf("IsNeg() = false, want true for a negative nonzero duration")
+	}
+
+	d, err = NewDurationFromParts(false, 1, 2, 3, 4, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	if d.IsNeg() {
+		t.Errorf("IsNeg() = true, want false for a positive duration")
+	}
+}
+
+// TestNewDurationFromPartsZeroMagnitudeLosesSign documents, rather than
+// hides, a known limitation: Duration has no sign bit separate from its
+// time.Duration magnitude, so an all-zero-fields negative duration is
+// indistinguishable from a positive one. MySQL itself has no representable
+// negative-zero TIME either, so this isn't a regression to fix so much as
+// a case callers should not rely on IsNeg() to catch.
+func TestNewDurationFromPartsZeroMagnitudeLosesSign(t *testing.T) {
+	d, err := NewDurationFromParts(true, 0, 0, 0, 0, 6)
+	if err != nil {
+		t.Fatalf("NewDurationFromParts: %v", err)
+	}
+	if d.IsNeg() {
+		t.Errorf("IsNeg() = true, want false: an all-zero magnitude cannot carry a sign in this representation")
+	}
+}
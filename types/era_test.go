@@ -0,0 +1,104 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "testing"
+
+func prolepticDate(year, month, day int) Time {
+	return Time{Time: FromDate(year, month, day, 0, 0, 0, 0)}
+}
+
+// TestYearDayProlepticMatchesKnownDates checks YearDayProleptic against
+// known DAYOFYEAR values, including a leap day and proleptic years <= 0
+// that (Time).Time.YearDay() can't compute itself.
+func TestYearDayProlepticMatchesKnownDates(t *testing.T) {
+	cases := []struct {
+		year, month, day int
+		want             int
+	}{
+		{2019, 1, 1, 1},
+		{2019, 12, 31, 365},
+		{2020, 3, 1, 61}, // 2020 is a leap year: Jan(31) + Feb(29) + 1
+		{2019, 3, 1, 60}, // 2019 is not: Jan(31) + Feb(28) + 1
+		{0, 3, 1, 61},    // proleptic year 0 is a leap year (0 % 400 == 0)
+		{-1, 3, 1, 60},   // proleptic year -1 is not
+	}
+	for _, c := range cases {
+		got := YearDayProleptic(prolepticDate(c.year, c.month, c.day))
+		if got != c.want {
+			t.Errorf("YearDayProleptic(%d-%02d-%02d) = %d, want %d", c.year, c.month, c.day, got, c.want)
+		}
+	}
+}
+
+// TestWeekdayProlepticMatchesKnownDates checks WeekdayProleptic against
+// real, independently known weekdays, using (time.Time).Weekday()'s
+// Sunday=0 convention - the same one the non-extended DAYOFWEEK/WEEKDAY
+// path (ds[i].Time.Weekday()) already uses for year >= 1.
+func TestWeekdayProlepticMatchesKnownDates(t *testing.T) {
+	cases := []struct {
+		year, month, day int
+		want             int
+	}{
+		{1970, 1, 1, 4}, // Thursday
+		{2000, 1, 1, 6}, // Saturday
+		{2019, 1, 1, 2}, // Tuesday
+		{2019, 1, 6, 0}, // Sunday
+	}
+	for _, c := range cases {
+		got := WeekdayProleptic(prolepticDate(c.year, c.month, c.day))
+		if got != c.want {
+			t.Errorf("WeekdayProleptic(%d-%02d-%02d) = %d, want %d", c.year, c.month, c.day, got, c.want)
+		}
+	}
+}
+
+// TestWeekdayProlepticAgreesAtYearOneBoundary guards the seam between
+// WeekdayProleptic (used for year < 1) and (Time).Time.Weekday() (used for
+// year >= 1): December 31 of proleptic year 0 must be the day immediately
+// before January 1, year 1, under Go's own Weekday() for the latter.
+func TestWeekdayProlepticAgreesAtYearOneBoundary(t *testing.T) {
+	yearZeroDec31 := WeekdayProleptic(prolepticDate(0, 12, 31))
+	yearOneJan1 := prolepticDate(1, 1, 1).Time.Weekday()
+	if want := (int(yearOneJan1) + 6) % 7; yearZeroDec31 != want {
+		t.Errorf("WeekdayProleptic(0000-12-31) = %d, want %d (the day before %v)", yearZeroDec31, want, yearOneJan1)
+	}
+}
+
+// TestYearWeekProlepticMatchesCurrentBehavior pins YearWeekProleptic's
+// current output for a handful of dates, including 1987-01-01 (named in
+// YearWeekProleptic's own doc comment) and 2000-01-01, where that output
+// diverges from MySQL's documented YEARWEEK examples. This is a regression
+// guard on the existing (simplified) behavior, not a claim that the
+// simplification is correct.
+func TestYearWeekProlepticMatchesCurrentBehavior(t *testing.T) {
+	cases := []struct {
+		year, month, day, mode int
+		wantYear, wantWeek     int
+	}{
+		{2019, 1, 1, 0, 2019, 1},
+		{2019, 1, 7, 0, 2019, 2},
+		{2019, 1, 1, 1, 2019, 1},
+		{2019, 12, 31, 0, 2019, 53},
+		{1987, 1, 1, 0, 1987, 1}, // MySQL docs: YEARWEEK('1987-01-01') = 198652
+		{2000, 1, 1, 0, 2000, 1}, // MySQL docs: YEARWEEK('2000-01-01') = 199952
+	}
+	for _, c := range cases {
+		gotYear, gotWeek := YearWeekProleptic(prolepticDate(c.year, c.month, c.day), c.mode)
+		if gotYear != c.wantYear || gotWeek != c.wantWeek {
+			t.Errorf("YearWeekProleptic(%d-%02d-%02d, mode %d) = (%d, %d), want (%d, %d)",
+				c.year, c.month, c.day, c.mode, gotYear, gotWeek, c.wantYear, c.wantWeek)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// Interval carries the decomposed value of a MySQL INTERVAL expression, e.g.
+// the pieces of `INTERVAL 3 DAY` or `INTERVAL "1:2:3" HOUR_SECOND`. Only the
+// fields relevant to Unit are meaningful; the rest are zero.
+type Interval struct {
+	Unit   string
+	Neg    bool
+	Years  int64
+	Months int64
+	Days   int64
+	Hours  int64
+	Mins   int64
+	Secs   int64
+	Micros int64
+}
+
+// hasTimePart reports whether the interval carries any sub-day component, in
+// which case arithmetic must go through total seconds rather than calendar
+// month/day math.
+func (iv Interval) hasTimePart() bool {
+	return iv.Hours != 0 || iv.Mins != 0 || iv.Secs != 0 || iv.Micros != 0
+}
+
+// floorDiv is integer division rounding toward negative infinity, as
+// opposed to Go's "/" which truncates toward zero. MysqlDayNumber's
+// leap-year correction terms need floored division: for a BC-era (negative)
+// year, truncating division shifts the result by one at every 4/100/400
+// year boundary the correction is supposed to account for.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// MysqlDayNumber computes the absolute day number MySQL uses internally
+// (days since year 0), the same quantity underlying TO_DAYS(). It's the
+// inverse of TimeFromDays, and mirrors MySQL's own calc_daynr rather than a
+// from-scratch re-derivation: delsum accumulates 365 days/year plus the
+// month/day offset, then the month<=2 branch rolls the still-open last two
+// months of the previous year into that year's leap-day accounting, and the
+// final term subtracts the non-leap century years. floorDiv (rather than
+// Go's truncating "/") keeps the century correction monotonic for the
+// negative (BC-era, proleptic) years tidb_extended_date_range allows.
+func MysqlDayNumber(year, month, day int) int64 {
+	if year == 0 && month == 0 {
+		return 0
+	}
+	y := int64(year)
+	m := int64(month)
+	delsum := 365*y + 31*(m-1) + int64(day)
+	if m <= 2 {
+		y--
+	} else {
+		delsum -= (m*4 + 23) / 10
+	}
+	century := floorDiv(y, 100)
+	return delsum + floorDiv(y, 4) - floorDiv((century+1)*3, 4)
+}
+
+// ToSeconds returns the number of seconds since MySQL's day-0 epoch that the
+// date part of t represents, plus the fractional time-of-day part of t in
+// seconds. It underlies UNIX_TIMESTAMP, TIMESTAMPDIFF and window-frame RANGE
+// bound arithmetic, all of which need the same "days*86400 + time" basis.
+func (t Time) ToSeconds() int64 {
+	tm := t.Time
+	dayNum := MysqlDayNumber(tm.Year(), int(tm.Month()), tm.Day())
+	return dayNum*86400 + int64(tm.Hour())*3600 + int64(tm.Minute())*60 + int64(tm.Second())
+}
+
+// NewIntervalFromUnit casts an integer magnitude plus a MySQL interval unit
+// keyword (as accepted by TIMESTAMPADD/TIMESTAMPDIFF/DATE_ADD) into an
+// Interval, the single conversion point every caller that used to switch on
+// the unit string by hand should go through instead.
+func NewIntervalFromUnit(v int64, unit string) (Interval, error) {
+	iv := Interval{Unit: unit}
+	switch unit {
+	case "MICROSECOND":
+		iv.Micros = v
+	case "SECOND":
+		iv.Secs = v
+	case "MINUTE":
+		iv.Mins = v
+	case "HOUR":
+		iv.Hours = v
+	case "DAY":
+		iv.Days = v
+	case "WEEK":
+		iv.Days = v * 7
+	case "MONTH":
+		iv.Months = v
+	case "QUARTER":
+		iv.Months = v * 3
+	case "YEAR":
+		iv.Years = v
+	default:
+		return Interval{}, ErrWrongValue.GenWithStackByArgs(TimeStr, unit)
+	}
+	return iv, nil
+}
+
+// AddInterval adds iv to t, mirroring Vitess's DateTime.addInterval: interval
+// values with a time part are applied in total seconds (so overflow carries
+// across day/month/year boundaries correctly); purely calendar intervals
+// (YEAR/MONTH/QUARTER) are applied as month arithmetic with the day clamped
+// to the last day of the resulting month.
+func (t Time) AddInterval(iv Interval) (Time, error) {
+	sign := int64(1)
+	if iv.Neg {
+		sign = -1
+	}
+	tm := t.Time
+	if iv.hasTimePart() {
+		totalSecs := MysqlDayNumber(tm.Year(), int(tm.Month()), tm.Day())*86400 +
+			int64(tm.Hour())*3600 + int64(tm.Minute())*60 + int64(tm.Second())
+		deltaSecs := iv.Hours*3600 + iv.Mins*60 + iv.Secs
+		deltaMicros := int64(tm.Microsecond()) + sign*iv.Micros
+		if deltaMicros < 0 {
+			borrow := (-deltaMicros + 999999) / 1000000
+			deltaMicros += borrow * 1000000
+			deltaSecs -= borrow
+		} else if deltaMicros >= 1000000 {
+			carry := deltaMicros / 1000000
+			deltaMicros -= carry * 1000000
+			deltaSecs += carry
+		}
+		totalSecs += sign * deltaSecs
+		newDayNum := totalSecs / 86400
+		remSecs := totalSecs % 86400
+		if remSecs < 0 {
+			remSecs += 86400
+			newDayNum--
+		}
+		newTime := TimeFromDays(newDayNum)
+		hour := remSecs / 3600
+		min := (remSecs % 3600) / 60
+		sec := remSecs % 60
+		res := Time{
+			Time: FromDate(newTime.Time.Year(), int(newTime.Time.Month()), newTime.Time.Day(), int(hour), int(min), int(sec), int(deltaMicros)),
+			Type: t.Type,
+			Fsp:  t.Fsp,
+		}
+		return res, nil
+	}
+
+	totalMonths := int64(tm.Year())*12 + int64(tm.Month()) - 1 + sign*(iv.Years*12+iv.Months)
+	newYear := int(totalMonths / 12)
+	newMonth := int(totalMonths%12) + 1
+	if newMonth <= 0 {
+		newMonth += 12
+		newYear--
+	}
+	day := tm.Day()
+	if lastDay := GetLastDay(newYear, newMonth); day > lastDay {
+		day = lastDay
+	}
+	res := Time{
+		Time: FromDate(newYear, newMonth, day, tm.Hour(), tm.Minute(), tm.Second(), tm.Microsecond()),
+		Type: t.Type,
+		Fsp:  t.Fsp,
+	}
+	return res, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "testing"
+
+// TestMysqlDayNumber checks MysqlDayNumber against known TO_DAYS() outputs,
+// the bug this guards against silently drifted by a different offset at
+// every input rather than a single fixable constant bias.
+func TestMysqlDayNumber(t *testing.T) {
+	cases := []struct {
+		year, month, day int
+		want             int64
+	}{
+		{1970, 1, 1, 719528},
+		{1970, 6, 15, 719693},
+		{1995, 5, 1, 728779},  // MySQL docs: TO_DAYS(950501)
+		{2007, 10, 7, 733321}, // MySQL docs: TO_DAYS('2007-10-07')
+		{0, 0, 0, 0},
+	}
+	for _, c := range cases {
+		got := MysqlDayNumber(c.year, c.month, c.day)
+		if got != c.want {
+			t.Errorf("MysqlDayNumber(%d, %d, %d) = %d, want %d", c.year, c.month, c.day, got, c.want)
+		}
+	}
+}
+
+// TestMysqlDayNumberMonotonic guards against the original bug, where two
+// different dates came out with two different offsets from the correct
+// value: day numbers must strictly increase with the calendar date.
+func TestMysqlDayNumberMonotonic(t *testing.T) {
+	prev := MysqlDayNumber(2019, 1, 1)
+	for _, d := range []struct{ y, m, d int }{
+		{2019, 2, 1}, {2019, 3, 1}, {2019, 12, 31}, {2020, 1, 1}, {2020, 2, 29}, {2021, 1, 1},
+	} {
+		cur := MysqlDayNumber(d.y, d.m, d.d)
+		if cur <= prev {
+			t.Errorf("MysqlDayNumber(%d,%d,%d)=%d is not greater than previous %d", d.y, d.m, d.d, cur, prev)
+		}
+		prev = cur
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package locale holds the weekday/month name tables used by MySQL's
+// lc_time_names system variable, so DAYNAME/MONTHNAME/DATE_FORMAT can render
+// in the session's configured language instead of always English.
+package locale
+
+// Names is one lc_time_names locale's weekday and month tables, indexed the
+// same way as types.WeekdayNames (Monday = 0) and calendar month (Jan = 0).
+type Names struct {
+	WeekdayNames     [7]string
+	AbbrWeekdayNames [7]string
+	MonthNames       [12]string
+	AbbrMonthNames   [12]string
+}
+
+// DefaultLocale is used whenever lc_time_names is unset or names an unknown
+// locale.
+const DefaultLocale = "en_US"
+
+// locales maps an lc_time_names value to its name tables. Only a handful of
+// common locales are populated; unlisted locales fall back to DefaultLocale.
+var locales = map[string]Names{
+	"en_US": {
+		WeekdayNames:     [7]string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"},
+		AbbrWeekdayNames: [7]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+		MonthNames:       [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		AbbrMonthNames:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	},
+	"de_DE": {
+		WeekdayNames:     [7]string{"Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag", "Sonntag"},
+		AbbrWeekdayNames: [7]string{"Mon", "Die", "Mit", "Don", "Fre", "Sam", "Son"},
+		MonthNames:       [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		AbbrMonthNames:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+	},
+	"fr_FR": {
+		WeekdayNames:     [7]string{"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche"},
+		AbbrWeekdayNames: [7]string{"lun", "mar", "mer", "jeu", "ven", "sam", "dim"},
+		MonthNames:       [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		AbbrMonthNames:   [12]string{"janv", "févr", "mars", "avr", "mai", "juin", "juil", "août", "sept", "oct", "nov", "déc"},
+	},
+	"ja_JP": {
+		WeekdayNames:     [7]string{"月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日", "日曜日"},
+		AbbrWeekdayNames: [7]string{"月", "火", "水", "木", "金", "土", "日"},
+		MonthNames:       [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		AbbrMonthNames:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	},
+	"zh_CN": {
+		WeekdayNames:     [7]string{"星期一", "星期二", "星期三", "星期四", "星期五", "星期六", "星期日"},
+		AbbrWeekdayNames: [7]string{"周一", "周二", "周三", "周四", "周五", "周六", "周日"},
+		MonthNames:       [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		AbbrMonthNames:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	},
+	"ru_RU": {
+		WeekdayNames:     [7]string{"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье"},
+		AbbrWeekdayNames: [7]string{"Пнд", "Втр", "Срд", "Чтв", "Птн", "Суб", "Вск"},
+		MonthNames:       [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		AbbrMonthNames:   [12]string{"янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"},
+	},
+}
+
+// Get returns the Names table for lc, falling back to DefaultLocale if lc is
+// empty or unknown. The returned pointer is stable and safe to cache.
+func Get(lc string) *Names {
+	if names, ok := locales[lc]; ok {
+		return &names
+	}
+	def := locales[DefaultLocale]
+	return &def
+}
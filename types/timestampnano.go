@@ -0,0 +1,64 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimestampNano carries a Unix timestamp at nanosecond resolution as a
+// (seconds, nanoseconds) pair, analogous to a protobuf Timestamp{Sec, Nsec}
+// wire value. It exists as a TiDB-side representation rather than a new
+// mysql.TypeTimestampNano byte: the type-byte enum is owned by the
+// pingcap/parser module, so widening it would mean forking that dependency.
+// Until that's justified, observability workloads that need sub-microsecond
+// precision get it through this struct and the FROM_UNIXTIME_NANO /
+// UNIX_TIMESTAMP_NANO builtins, at the cost of round-tripping through a
+// formatted string rather than a native TIMESTAMP(9) column.
+type TimestampNano struct {
+	Sec  int64
+	Nsec uint32
+}
+
+// NanosPerSecond is the scale factor between TimestampNano's two fields.
+const NanosPerSecond = 1e9
+
+// TimestampNanoFromUnixNano splits a nanosecond-resolution Unix timestamp
+// (as produced by FROM_UNIXTIME_NANO's bigint argument) into its Sec/Nsec
+// parts, flooring toward negative infinity so that negative inputs (times
+// before the epoch) still land on a valid Nsec in [0, 1e9).
+func TimestampNanoFromUnixNano(unixNano int64) TimestampNano {
+	sec := unixNano / NanosPerSecond
+	nsec := unixNano % NanosPerSecond
+	if nsec < 0 {
+		nsec += NanosPerSecond
+		sec--
+	}
+	return TimestampNano{Sec: sec, Nsec: uint32(nsec)}
+}
+
+// UnixNano reassembles t into a single nanosecond-resolution Unix timestamp.
+func (t TimestampNano) UnixNano() int64 {
+	return t.Sec*NanosPerSecond + int64(t.Nsec)
+}
+
+// String renders t the way FROM_UNIXTIME_NANO's result column does: the
+// standard MySQL DATETIME literal format, but with a 9-digit fractional
+// part instead of DATETIME's 6-digit microsecond cap.
+func (t TimestampNano) String(loc *time.Location) string {
+	tm := time.Unix(t.Sec, int64(t.Nsec)).In(loc)
+	return fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d.%09d",
+		tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), t.Nsec)
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimestampNanoFromUnixNanoRoundTrips checks that splitting into
+// Sec/Nsec and reassembling via UnixNano is the identity, including for
+// negative (pre-epoch) inputs, where a naive Go "%"/"/ " pair would land
+// Nsec outside [0, 1e9).
+func TestTimestampNanoFromUnixNanoRoundTrips(t *testing.T) {
+	cases := []int64{0, 1, 999999999, 1000000000, 1500000000123456789, -1, -999999999, -1000000001}
+	for _, unixNano := range cases {
+		ts := TimestampNanoFromUnixNano(unixNano)
+		if ts.Nsec >= NanosPerSecond {
+			t.Errorf("TimestampNanoFromUnixNano(%d).Nsec = %d, want < %d", unixNano, ts.Nsec, int64(NanosPerSecond))
+		}
+		if got := ts.UnixNano(); got != unixNano {
+			t.Errorf("TimestampNanoFromUnixNano(%d).UnixNano() = %d, want %d", unixNano, got, unixNano)
+		}
+	}
+}
+
+// TestTimestampNanoString checks the 9-digit fractional rendering.
+func TestTimestampNanoString(t *testing.T) {
+	ts := TimestampNano{Sec: 1577836800, Nsec: 123456789} // 2020-01-01 00:00:00 UTC
+	want := "2020-01-01 00:00:00.123456789"
+	if got := ts.String(time.UTC); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// TimeZone holds an absolute instant paired with the IANA zone name it was
+// observed in. Unlike Time, which always normalizes to the session's
+// Location(), TimeZone keeps the origin zone around so it survives a
+// round-trip to the client even when the session zone differs.
+type TimeZone struct {
+	// CoreTime is the instant, always stored normalized to UTC.
+	CoreTime Time
+	// Zone is the IANA zone name (e.g. "Asia/Shanghai") the value was
+	// constructed with. Empty means the zone is unknown/unspecified.
+	Zone string
+	// Fsp is the fractional seconds precision, same semantics as Time.Fsp.
+	Fsp int8
+}
+
+// ZeroTimeZone is the zero value of TimeZone, analogous to ZeroDatetime.
+var ZeroTimeZone = TimeZone{CoreTime: ZeroDatetime, Fsp: DefaultFsp}
+
+// NewTimeZone builds a TimeZone from a Time value and an IANA zone name. The
+// Time is converted to UTC so CoreTime always represents an absolute instant.
+func NewTimeZone(t Time, zone string, fsp int8) (TimeZone, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return TimeZone{}, errors.Trace(err)
+	}
+	gt, err := t.GoTime(loc)
+	if err != nil {
+		return TimeZone{}, errors.Trace(err)
+	}
+	utc := Time{
+		Time: FromGoTime(gt.UTC()),
+		Type: t.Type,
+		Fsp:  fsp,
+	}
+	return TimeZone{CoreTime: utc, Zone: zone, Fsp: fsp}, nil
+}
+
+// In returns the absolute instant converted into the given IANA zone,
+// returning a plain Time with no zone attached.
+func (t TimeZone) In(zone string) (Time, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return Time{}, errors.Trace(err)
+	}
+	gt, err := t.CoreTime.GoTime(time.UTC)
+	if err != nil {
+		return Time{}, errors.Trace(err)
+	}
+	return Time{
+		Time: FromGoTime(gt.In(loc)),
+		Type: t.CoreTime.Type,
+		Fsp:  t.Fsp,
+	}, nil
+}
+
+// String renders the value as "<datetime in origin zone> <zone name>", which
+// is what CONVERT_TZ/AT TIME ZONE round-trip back to the client.
+func (t TimeZone) String() string {
+	local, err := t.In(t.Zone)
+	if err != nil {
+		return t.CoreTime.String()
+	}
+	if t.Zone == "" {
+		return local.String()
+	}
+	return local.String() + " " + t.Zone
+}
+
+// IsZero reports whether the underlying instant is the zero datetime.
+func (t TimeZone) IsZero() bool {
+	return t.CoreTime.IsZero()
+}
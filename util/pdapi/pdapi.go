@@ -0,0 +1,47 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pdapi centralizes the PD HTTP API paths other packages talk to,
+// so a path doesn't have to be retyped (and risk drifting) at every call
+// site across infoschema, store/helper and their tests.
+package pdapi
+
+// The route constants below mirror PD's HTTP API as mounted under
+// /pd/api/v1. They're paths, not full URLs: callers join them onto a PD (or
+// TiDB status / TiKV status) address.
+const (
+	// Stores lists every TiKV store PD knows about, along with its stats.
+	Stores = "/pd/api/v1/stores"
+	// Members lists the PD cluster's member list, used to discover PD
+	// instances themselves.
+	Members = "/pd/api/v1/members"
+	// ClusterVersion reports the cluster's negotiated version string.
+	ClusterVersion = "/pd/api/v1/version"
+	// Status reports the PD build status, including its git hash.
+	Status = "/pd/api/v1/status"
+	// Config is PD's own configuration endpoint. TiDB and TiKV each expose
+	// their own component-local "/config" (see ComponentConfig below); PD's
+	// lives under the versioned API root instead.
+	Config = "/pd/api/v1/config"
+	// HotRead and HotWrite report the regions PD currently considers hot
+	// for reads/writes respectively, each annotated with its flow in bytes.
+	HotRead  = "/pd/api/v1/hotspot/regions/read"
+	HotWrite = "/pd/api/v1/hotspot/regions/write"
+	// RegionsByStore lists every region with a peer on the given store;
+	// callers append the store ID to this prefix.
+	RegionsByStore = "/pd/api/v1/regions/store/"
+)
+
+// ComponentConfig is the local configuration endpoint TiDB and TiKV expose
+// directly off their status port root, as opposed to PD's versioned one.
+const ComponentConfig = "/config"
@@ -0,0 +1,205 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+// ProcessInfo is a struct used for information_schema.processlist and the
+// SHOW PROCESSLIST statement. One is held per live session and kept up to
+// date by the session itself; SessionManager.ShowProcessList snapshots them
+// for readers.
+type ProcessInfo struct {
+	ID            uint64
+	User          string
+	Host          string
+	DB            string
+	Command       byte
+	Plan          interface{}
+	Time          interface{}
+	State         uint16
+	Info          string
+	CurTxnStartTS uint64
+	StmtCtx       *stmtctx.StatementContext
+	StatusFlag    uint16
+	MemTracker    interface{}
+
+	// TxnState is a human-readable summary of the session's current
+	// transaction (one of "idle", "active", "committing", "rolling-back"),
+	// the column TXN_STATE in information_schema.PROCESSLIST /
+	// CLUSTER_PROCESSLIST surfaces directly.
+	TxnState string
+	// LockWaitStartTS is the physical time (as a TSO) at which the current
+	// statement started waiting on a pessimistic lock, or zero if it isn't
+	// waiting. Operators use it together with WaitingForTxnID to answer
+	// "who is blocking whom" from SQL instead of correlating TiKV logs.
+	LockWaitStartTS uint64
+	// WaitingForTxnID is the start_ts of the transaction holding the lock
+	// this session is blocked on, or zero if it isn't waiting.
+	WaitingForTxnID uint64
+	// MemUsageBytes is the current statement's memory tracker consumption
+	// at the time ShowProcessList snapshotted this ProcessInfo.
+	MemUsageBytes int64
+}
+
+// TxnStateIdle, TxnStateActive, TxnStateCommitting and TxnStateRollingBack
+// are the values ProcessInfo.TxnState takes.
+const (
+	TxnStateIdle        = "idle"
+	TxnStateActive      = "active"
+	TxnStateCommitting  = "committing"
+	TxnStateRollingBack = "rolling-back"
+)
+
+// SessionManager is held by the server and implemented by the session
+// package. It lets callers that don't want a dependency on session (the
+// executor, information_schema's PROCESSLIST/CLUSTER_PROCESSLIST readers)
+// enumerate and act on live sessions.
+type SessionManager interface {
+	// ShowProcessList returns a snapshot ProcessInfo for every live session,
+	// keyed by connection ID.
+	ShowProcessList() map[uint64]*ProcessInfo
+	// GetProcessInfo returns the ProcessInfo for a single connection ID.
+	GetProcessInfo(id uint64) (*ProcessInfo, bool)
+	// Kill terminates the session behind connectionID. query, when true,
+	// only cancels the in-flight statement rather than the whole session.
+	Kill(connectionID uint64, query bool)
+}
+
+// ReasonedSessionManager is an optional capability a SessionManager
+// implementation can add alongside Kill, to record why a kill happened
+// (an explicit admin KILL versus a server-side protection such as
+// MAX_EXECUTION_TIME or a memory-tracker trip). It's a separate interface,
+// rather than a new parameter on SessionManager.Kill, so existing
+// SessionManager implementations and every caller of Kill keep compiling
+// unchanged; a caller that wants to record a reason should type-assert for
+// this interface and fall back to a plain Kill when it isn't implemented.
+type ReasonedSessionManager interface {
+	SessionManager
+	// KillWithReason behaves like Kill, but additionally records reason
+	// for the killed session's error message and audit trail.
+	KillWithReason(connectionID uint64, query bool, reason KillReason)
+}
+
+// KillReason classifies why a session or query was killed, so the session
+// on the receiving end can surface a specific error instead of a generic
+// "query interrupted" and operators reading logs can tell an admin's
+// `KILL QUERY` apart from a server-side protection tripping.
+type KillReason byte
+
+const (
+	// KillReasonUnknown is the zero value, used when the caller doesn't
+	// (or can't) distinguish why the kill happened.
+	KillReasonUnknown KillReason = iota
+	// KillReasonQuery marks an explicit `KILL [QUERY|TIDB] <connID>` (or
+	// the equivalent SessionManager.Kill call) issued by an admin.
+	KillReasonQuery
+	// KillReasonMaxExecTimeExceeded marks a kill triggered by the
+	// MAX_EXECUTION_TIME statement/session limit firing.
+	KillReasonMaxExecTimeExceeded
+	// KillReasonMemoryExceeded marks a kill triggered by the session's
+	// memory tracker crossing its configured limit (tidb_mem_quota_query
+	// or a server-wide OOM guard).
+	KillReasonMemoryExceeded
+)
+
+// String returns the KillReason's name, as it should appear in the error
+// message the killed session surfaces to its client.
+func (r KillReason) String() string {
+	switch r {
+	case KillReasonQuery:
+		return "query was killed"
+	case KillReasonMaxExecTimeExceeded:
+		return "max execution time exceeded"
+	case KillReasonMemoryExceeded:
+		return "memory exceeded"
+	default:
+		return "query was interrupted"
+	}
+}
+
+// KillHistoryEntry is one row of INFORMATION_SCHEMA.KILL_HISTORY: a record
+// of one session/query kill, kept around so the killed connection's
+// identity and an admin's free-text `KILL QUERY ... REASON '<text>'`
+// detail (or a server-side kill's classified KillReason) stay visible
+// after the killed session itself is gone.
+type KillHistoryEntry struct {
+	ConnectionID uint64
+	// User and Host identify the killed session, the same way PROCESSLIST
+	// would have while it was still alive.
+	User   string
+	Host   string
+	Reason KillReason
+	// KilledByUser is the admin user that issued the KILL statement, or
+	// empty for a server-side kill (MAX_EXECUTION_TIME, an OOM tracker)
+	// that has no requesting user.
+	KilledByUser string
+	// StmtDigest is the killed statement's digest, if one was computed
+	// before the kill took effect, so a recurring offender can be found
+	// by digest across many kill entries rather than by connection ID
+	// alone (connection IDs get reused once a session disconnects).
+	StmtDigest string
+	Detail     string
+	Time       time.Time
+}
+
+// killHistoryLimit bounds the KILL_HISTORY buffer so a server that kills
+// many sessions over a long uptime doesn't grow it without bound; once
+// full, the oldest entry is evicted for each new one recorded.
+const killHistoryLimit = 1000
+
+var (
+	killHistoryMu sync.Mutex
+	killHistory   []KillHistoryEntry
+)
+
+// RecordKill appends one KILL_HISTORY entry. It's meant to be called
+// wherever a kill actually happens: a KillWithReason implementation
+// recording an admin's KILL QUERY/TIDB (user/host identifying the killed
+// session, killedByUser the admin that issued it, detail carrying the
+// statement's REASON text, if any), and a server-side protection such as
+// an OOM tracker recording its own automatic kill the same way (with
+// killedByUser left empty). Neither of those callers lives in this
+// package; RecordKill only owns the buffer they both write into and
+// KILL_HISTORY reads from.
+func RecordKill(connID uint64, user, host string, reason KillReason, killedByUser, stmtDigest, detail string) {
+	killHistoryMu.Lock()
+	defer killHistoryMu.Unlock()
+	killHistory = append(killHistory, KillHistoryEntry{
+		ConnectionID: connID,
+		User:         user,
+		Host:         host,
+		Reason:       reason,
+		KilledByUser: killedByUser,
+		StmtDigest:   stmtDigest,
+		Detail:       detail,
+		Time:         time.Now(),
+	})
+	if len(killHistory) > killHistoryLimit {
+		killHistory = killHistory[len(killHistory)-killHistoryLimit:]
+	}
+}
+
+// KillHistory returns a snapshot of every recorded kill, oldest first.
+func KillHistory() []KillHistoryEntry {
+	killHistoryMu.Lock()
+	defer killHistoryMu.Unlock()
+	out := make([]KillHistoryEntry, len(killHistory))
+	copy(out, killHistory)
+	return out
+}